@@ -0,0 +1,162 @@
+package gofindit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Aggregation summarizes a field over the documents matching a search
+type Aggregation struct {
+	Type  string `json:"type"` // "count", "min", "max", "sum", "avg" or "terms"
+	Field string `json:"field"`
+	Size  int    `json:"size"` // Max terms buckets, defaults to 10
+}
+
+func (a *Aggregation) Sanatize() {
+	// If size is 0, set it to 10
+	if a.Type == "terms" && a.Size == 0 {
+		a.Size = 10
+	}
+}
+
+func (a *Aggregation) Validate() error {
+	if a.Field == "" {
+		return fmt.Errorf("aggregation field cannot be empty")
+	}
+
+	switch a.Type {
+	case "count", "min", "max", "sum", "avg", "terms":
+	default:
+		return fmt.Errorf("invalid aggregation type %s", a.Type)
+	}
+
+	if a.Size < 0 {
+		return fmt.Errorf("aggregation size cannot be negative")
+	}
+
+	return nil
+}
+
+// AggregationResult is the result of an Aggregation. Count is the number
+// of values of the field in the matching documents, Value is the min,
+// max, sum or avg and Buckets are the most common terms
+type AggregationResult struct {
+	Count   int      `json:"count"`
+	Value   float64  `json:"value"`
+	Buckets []Bucket `json:"buckets,omitempty"`
+}
+
+// Bucket is a term and the number of times it was found
+type Bucket struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Aggregate runs the aggregations over every document matching the
+// search query fields. Values are read from the fields doc values
+func (i *Index) Aggregate(searchQuery SearchQuery, aggs map[string]Aggregation) (map[string]AggregationResult, error) {
+	return i.AggregateContext(context.Background(), searchQuery, aggs)
+}
+
+// AggregateContext runs the aggregations, matching stops with the context
+// error if the context is done or ErrTimedOut if the query timeout is reached
+func (i *Index) AggregateContext(ctx context.Context, searchQuery SearchQuery, aggs map[string]Aggregation) (map[string]AggregationResult, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	searchQuery.Sanatize()
+	err := searchQuery.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	results, timedOut, err := i.matches(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if timedOut {
+		return nil, ErrTimedOut
+	}
+
+	response := make(map[string]AggregationResult, len(aggs))
+	for name, agg := range aggs {
+		agg.Sanatize()
+		if err := agg.Validate(); err != nil {
+			return nil, fmt.Errorf("aggregation %s: %v", name, err)
+		}
+
+		result, err := i.aggregate(results, agg)
+		if err != nil {
+			return nil, fmt.Errorf("aggregation %s: %v", name, err)
+		}
+		response[name] = result
+	}
+
+	return response, nil
+}
+
+// aggregate runs a single aggregation over the documents
+func (i *Index) aggregate(docs []*Document, agg Aggregation) (AggregationResult, error) {
+	var result AggregationResult
+
+	dv := i.columnFor(agg.Field, docs)
+	if dv == nil {
+		return result, nil
+	}
+
+	switch agg.Type {
+	case "min", "max", "sum", "avg":
+		if !dv.numeric() {
+			return result, fmt.Errorf("cannot use %s on %s field %s", agg.Type, dv.typ, agg.Field)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, doc := range docs {
+		for _, value := range dv.get(doc.num) {
+			result.Count++
+
+			switch agg.Type {
+			case "min":
+				if result.Count == 1 {
+					result.Value = math.Inf(1)
+				}
+				result.Value = math.Min(result.Value, value.num)
+			case "max":
+				if result.Count == 1 {
+					result.Value = math.Inf(-1)
+				}
+				result.Value = math.Max(result.Value, value.num)
+			case "sum", "avg":
+				result.Value += value.num
+			case "terms":
+				counts[value.term]++
+			}
+		}
+	}
+
+	if agg.Type == "avg" && result.Count > 0 {
+		result.Value /= float64(result.Count)
+	}
+
+	if agg.Type == "terms" {
+		for key, count := range counts {
+			result.Buckets = append(result.Buckets, Bucket{Key: key, Count: count})
+		}
+
+		// Most common first, ties by key so the buckets are stable
+		sort.Slice(result.Buckets, func(a, b int) bool {
+			if result.Buckets[a].Count != result.Buckets[b].Count {
+				return result.Buckets[a].Count > result.Buckets[b].Count
+			}
+			return result.Buckets[a].Key < result.Buckets[b].Key
+		})
+		if len(result.Buckets) > agg.Size {
+			result.Buckets = result.Buckets[:agg.Size]
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package gofindit
+
+import "testing"
+
+func TestIndex_Aggregate(t *testing.T) {
+	index := newTestRecordIndex(t)
+	index.Index("tom", TestRecord{Name: "tom", Score: 10, Note: "a"})
+
+	results, err := index.Aggregate(SearchQuery{}, map[string]Aggregation{
+		"count":   {Type: "count", Field: "balance"},
+		"min":     {Type: "min", Field: "score"},
+		"max":     {Type: "max", Field: "score"},
+		"sum":     {Type: "sum", Field: "score"},
+		"avg":     {Type: "avg", Field: "score"},
+		"scores":  {Type: "terms", Field: "score", Size: 1},
+		"notes":   {Type: "terms", Field: "note"},
+		"unknown": {Type: "max", Field: "unknown"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]float64{"min": -3, "max": 10, "sum": 26, "avg": 6.5}
+	for name, want := range values {
+		if results[name].Value != want {
+			t.Errorf("expected %s to be %v, got %v", name, want, results[name].Value)
+		}
+		if results[name].Count != 4 {
+			t.Errorf("expected %s count to be 4, got %d", name, results[name].Count)
+		}
+	}
+
+	if results["count"].Count != 1 {
+		t.Errorf("expected count to be 1, got %d", results["count"].Count)
+	}
+	if results["unknown"].Count != 0 {
+		t.Errorf("expected unknown count to be 0, got %d", results["unknown"].Count)
+	}
+
+	scores := results["scores"].Buckets
+	if len(scores) != 1 || scores[0] != (Bucket{Key: "10", Count: 2}) {
+		t.Errorf("expected top score bucket 10 with 2, got %+v", scores)
+	}
+
+	notes := results["notes"].Buckets
+	want := []Bucket{{Key: "a", Count: 2}, {Key: "b", Count: 1}, {Key: "c", Count: 1}}
+	if len(notes) != len(want) {
+		t.Fatalf("expected %d note buckets, got %+v", len(want), notes)
+	}
+	for i := range want {
+		if notes[i] != want[i] {
+			t.Errorf("expected note bucket %+v at %d, got %+v", want[i], i, notes[i])
+		}
+	}
+}
+
+func TestIndex_Aggregate_termsSlice(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	results, err := index.Aggregate(SearchQuery{}, map[string]Aggregation{
+		"tags": {Type: "terms", Field: "tags"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each item of a slice is its own bucket
+	tags := results["tags"].Buckets
+	want := []Bucket{{Key: "c", Count: 2}, {Key: "a", Count: 1}, {Key: "b", Count: 1}, {Key: "x", Count: 1}, {Key: "z", Count: 1}}
+	if len(tags) != len(want) {
+		t.Fatalf("expected %d tag buckets, got %+v", len(want), tags)
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Errorf("expected tag bucket %+v at %d, got %+v", want[i], i, tags[i])
+		}
+	}
+}
+
+func TestIndex_Aggregate_filtered(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	results, err := index.Aggregate(SearchQuery{
+		Fields: []SearchQueryField{{Field: "score", Type: "range", Value: []int{0, 100}}},
+	}, map[string]Aggregation{"avg": {Type: "avg", Field: "score"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results["avg"].Value != 9.5 {
+		t.Errorf("expected avg of 9.5, got %v", results["avg"].Value)
+	}
+
+	_, err = index.Aggregate(SearchQuery{}, map[string]Aggregation{"sum": {Type: "sum", Field: "name"}})
+	if err == nil {
+		t.Errorf("expected sum on a text field to error")
+	}
+
+	_, err = index.Aggregate(SearchQuery{}, map[string]Aggregation{"bad": {Type: "median", Field: "score"}})
+	if err == nil {
+		t.Errorf("expected invalid aggregation type to error")
+	}
+}
@@ -0,0 +1,169 @@
+package gofindit
+
+import (
+	"context"
+	"fmt"
+)
+
+// Batch stages index, upsert and delete operations and commits them
+// together. Searches see either none or all of the operations and if
+// any operation fails the ones before it are rolled back. A batch is
+// not safe to use from more than one goroutine
+type Batch struct {
+	index *Index
+	ops   []BulkOperation
+	docs  []*Document
+}
+
+// NewBatch returns an empty batch for the index
+func (i *Index) NewBatch() *Batch {
+	return &Batch{index: i}
+}
+
+// Index stages indexing a new document, the id must not be taken when committed
+func (b *Batch) Index(id string, doc any) error {
+	return b.stage(BulkOperation{Type: "index", ID: id, Document: doc})
+}
+
+// Upsert stages indexing a document, replacing the document with the id if there is one
+func (b *Batch) Upsert(id string, doc any) error {
+	return b.stage(BulkOperation{Type: "upsert", ID: id, Document: doc})
+}
+
+// Update stages replacing a document, it must exist when committed
+func (b *Batch) Update(id string, doc any) error {
+	return b.stage(BulkOperation{Type: "update", ID: id, Document: doc})
+}
+
+// Delete stages deleting a document, it must exist when committed
+func (b *Batch) Delete(id string) error {
+	return b.stage(BulkOperation{Type: "delete", ID: id})
+}
+
+// Add stages an operation, like an update with version conditions
+func (b *Batch) Add(op BulkOperation) error {
+	return b.stage(op)
+}
+
+// Len returns the number of staged operations
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Reset removes every staged operation
+func (b *Batch) Reset() {
+	b.ops = nil
+	b.docs = nil
+}
+
+// stage validates the operation and analyzes its document
+// so committing only has to check the ids are still valid
+func (b *Batch) stage(op BulkOperation) error {
+	doc, err := op.analyze()
+	if err != nil {
+		return err
+	}
+
+	b.ops = append(b.ops, op)
+	b.docs = append(b.docs, doc)
+
+	return nil
+}
+
+// Commit applies every staged operation at once. If one fails the
+// index is rolled back and the error says which operation it was.
+// The batch is reset after it is committed
+func (b *Batch) Commit() error {
+	return b.CommitContext(context.Background())
+}
+
+// CommitContext commits the batch unless the context is done before
+// it is applied, then the context error is returned
+func (b *Batch) CommitContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	i := b.index
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Waiting for the lock could take a while
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := i.applyAll(b.ops, b.docs)
+	if err != nil {
+		return err
+	}
+
+	b.Reset()
+	return nil
+}
+
+// applyAll applies the analyzed operations in order and undoes
+// them all if one fails. The lock must be held
+func (i *Index) applyAll(ops []BulkOperation, docs []*Document) error {
+	// Vector fields and doc values created by the operations
+	// are removed on rollback so they dont set the field types
+	vectors := make(map[string]bool, len(i.vectors))
+	for name := range i.vectors {
+		vectors[name] = true
+	}
+	columns := make(map[string]bool, len(i.docValues))
+	for name := range i.docValues {
+		columns[name] = true
+	}
+
+	// The document each operation replaced, nil if there was none
+	previous := make([]*Document, 0, len(ops))
+	seqNo := i.seqNo
+
+	// Tombstones of the ids before the batch changed them
+	tombstones := make(map[string]tombstone)
+
+	for n, op := range ops {
+		previous = append(previous, i.Documents[op.ID])
+		if _, ok := tombstones[op.ID]; !ok {
+			tombstones[op.ID] = i.tombstones[op.ID]
+		}
+
+		err := i.applyBulk(op, docs[n])
+		if err == nil {
+			continue
+		}
+
+		for undo := n - 1; undo >= 0; undo-- {
+			if doc, ok := i.Documents[ops[undo].ID]; ok {
+				i.removeDocument(doc)
+			}
+			if previous[undo] != nil {
+				// Cannot fail, the document was in the index before
+				_ = i.restoreDocument(previous[undo])
+			}
+		}
+		i.seqNo = seqNo
+		for id, tombstone := range tombstones {
+			if tombstone.version > 0 {
+				i.tombstones[id] = tombstone
+			} else {
+				delete(i.tombstones, id)
+			}
+		}
+		for name := range i.vectors {
+			if !vectors[name] {
+				delete(i.vectors, name)
+			}
+		}
+		for name := range i.docValues {
+			if !columns[name] {
+				delete(i.docValues, name)
+			}
+		}
+
+		return fmt.Errorf("batch operation %d %s %s: %w", n, op.Type, op.ID, err)
+	}
+
+	return nil
+}
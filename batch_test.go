@@ -0,0 +1,179 @@
+package gofindit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBatch_Commit(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	batch := index.NewBatch()
+	if err := batch.Delete("molly"); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Index("molly2", TestRecord{Name: "molly", Score: 30}); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Upsert("billy", TestRecord{Name: "billy", Score: 31}); err != nil {
+		t.Fatal(err)
+	}
+	if batch.Len() != 3 {
+		t.Errorf("expected 3 staged operations, got %d", batch.Len())
+	}
+
+	// Nothing is applied until the batch is committed
+	if _, err := index.Get("molly2"); err == nil {
+		t.Errorf("expected staged document to not be indexed")
+	}
+
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if batch.Len() != 0 {
+		t.Errorf("expected batch to be reset after commit")
+	}
+
+	if _, err := index.Get("molly"); err == nil {
+		t.Errorf("expected molly to be deleted")
+	}
+	results, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "score", Type: "range", Value: [2]int{30, 40}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected molly2 and billy, got %+v", results)
+	}
+}
+
+func TestBatch_Rollback(t *testing.T) {
+	type Test struct {
+		Name   string    `find:"name"`
+		Score  int       `find:"score"`
+		Vector []float32 `find:"vector"`
+	}
+
+	index := New()
+	if err := index.Index("1", Test{Name: "billy", Score: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Index("2", Test{Name: "sally", Score: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		stage func(b *Batch) error
+	}{
+		{"id taken", func(b *Batch) error {
+			if err := b.Upsert("1", Test{Name: "billy", Score: 10}); err != nil {
+				return err
+			}
+			if err := b.Delete("2"); err != nil {
+				return err
+			}
+			if err := b.Index("3", Test{Name: "molly", Score: 3, Vector: []float32{1, 2}}); err != nil {
+				return err
+			}
+			return b.Index("1", Test{Name: "tommy"})
+		}},
+		{"missing delete", func(b *Batch) error {
+			if err := b.Delete("1"); err != nil {
+				return err
+			}
+			return b.Delete("1")
+		}},
+		{"vector dims", func(b *Batch) error {
+			if err := b.Index("3", Test{Name: "molly", Vector: []float32{1, 2}}); err != nil {
+				return err
+			}
+			return b.Index("4", Test{Name: "tommy", Vector: []float32{1, 2, 3}})
+		}},
+	}
+
+	// Seq nos and document numbers are put back as they were
+	seqNo := index.seqNo
+	nums := map[string]int{"1": index.Documents["1"].num, "2": index.Documents["2"].num}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := index.NewBatch()
+			if err := tt.stage(batch); err != nil {
+				t.Fatal(err)
+			}
+			if err := batch.Commit(); err == nil {
+				t.Fatalf("expected commit to fail")
+			}
+
+			if len(index.Documents) != 2 {
+				t.Errorf("expected 2 documents after rollback, got %d", len(index.Documents))
+			}
+			for id, score := range map[string]int{"1": 1, "2": 2} {
+				doc, err := index.Get(id)
+				if err != nil {
+					t.Fatalf("expected %s to be restored, got %v", id, err)
+				}
+				if doc.(Test).Score != score {
+					t.Errorf("expected %s to have score %d, got %d", id, score, doc.(Test).Score)
+				}
+			}
+			if index.seqNo != seqNo {
+				t.Errorf("expected seq no %d after rollback, got %d", seqNo, index.seqNo)
+			}
+			for id, num := range nums {
+				if index.Documents[id].num != num {
+					t.Errorf("expected %s to keep number %d, got %d", id, num, index.Documents[id].num)
+				}
+			}
+			if _, ok := index.vectors["vector"]; ok {
+				t.Errorf("expected vector field created by the batch to be removed")
+			}
+
+			results, err := index.Search(SearchQuery{
+				Fields: []SearchQueryField{{Field: "score", Type: "range", Value: [2]int{1, 2}}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 2 {
+				t.Errorf("expected restored documents to be searchable, got %+v", results)
+			}
+		})
+	}
+}
+
+func TestBatch_stage(t *testing.T) {
+	batch := New().NewBatch()
+
+	if err := batch.Index("", TestRecord{}); err == nil {
+		t.Errorf("expected empty id to fail")
+	}
+	if err := batch.Upsert("1", nil); err == nil {
+		t.Errorf("expected nil document to fail")
+	}
+	if err := batch.Index("1", "not a struct"); err == nil {
+		t.Errorf("expected invalid document to fail")
+	}
+	if batch.Len() != 0 {
+		t.Errorf("expected invalid operations to not be staged")
+	}
+}
+
+func TestBatch_CommitContext(t *testing.T) {
+	index := New()
+	batch := index.NewBatch()
+	if err := batch.Index("1", TestRecord{Name: "billy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := batch.CommitContext(ctx); err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if len(index.Documents) != 0 || batch.Len() != 1 {
+		t.Errorf("expected canceled batch to not be applied")
+	}
+}
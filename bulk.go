@@ -0,0 +1,204 @@
+package gofindit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// BulkOperation is a single index, upsert, update or delete in a Bulk
+// request. Index creates a document, upsert creates or replaces it and
+// update and delete need it to exist
+type BulkOperation struct {
+	Type     string `json:"type"` // "index", "upsert", "update" or "delete"
+	ID       string `json:"id"`
+	Document any    `json:"document"` // Not used by delete
+
+	WriteOptions
+}
+
+func (bo *BulkOperation) Validate() error {
+	if bo.ID == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	switch bo.Type {
+	case "index", "upsert", "update":
+		if bo.Document == nil {
+			return fmt.Errorf("%s document cannot be nil", bo.Type)
+		}
+	case "delete":
+	default:
+		return fmt.Errorf("invalid bulk operation type %s", bo.Type)
+	}
+
+	if bo.Type == "index" && (bo.IfVersion > 0 || bo.IfSeqNo > 0) {
+		return fmt.Errorf("if_version and if_seq_no cannot be used with index")
+	}
+
+	return bo.WriteOptions.Validate()
+}
+
+// analyze validates the operation and turns its document into
+// a Document. Deletes have no document so nil is returned
+func (bo *BulkOperation) analyze() (*Document, error) {
+	if err := bo.Validate(); err != nil {
+		return nil, err
+	}
+	if bo.Type == "delete" {
+		return nil, nil
+	}
+
+	doc, err := NewDoc(bo.Document)
+	if err != nil {
+		return nil, err
+	}
+	doc.ID = bo.ID
+
+	return doc, nil
+}
+
+// BulkResponse has the result of every operation in the order they were
+// given. Errors is true if any operation failed
+type BulkResponse struct {
+	Items  []BulkItem `json:"items"`
+	Errors bool       `json:"errors"`
+}
+
+// BulkItem is the result of a single operation, Error is nil if it
+// succeeded. Version is the version of the written document
+type BulkItem struct {
+	Type         string `json:"type"`
+	ID           string `json:"id"`
+	Version      int64  `json:"version,omitempty"`
+	SeqNo        int64  `json:"seq_no,omitempty"`
+	Error        error  `json:"-"`
+	ErrorMessage string `json:"error,omitempty"` // Error as a string for json
+}
+
+// Bulk runs a batch of operations. Documents are analyzed in parallel
+// before the index is locked, then every operation is applied in order
+// in one go. An operation failing does not stop the rest
+func (i *Index) Bulk(ops []BulkOperation) (*BulkResponse, error) {
+	return i.BulkContext(context.Background(), ops)
+}
+
+// BulkContext runs a batch of operations unless the context is done
+// before they are applied, then the context error is returned and
+// none of the operations are applied
+func (i *Index) BulkContext(ctx context.Context, ops []BulkOperation) (*BulkResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	response := &BulkResponse{Items: make([]BulkItem, len(ops))}
+	docs, err := analyzeBulk(ctx, ops, response.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Waiting for the lock could take a while
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for n, op := range ops {
+		if response.Items[n].Error == nil {
+			response.Items[n].Error = i.applyBulk(op, docs[n])
+		}
+		if response.Items[n].Error != nil {
+			response.Items[n].ErrorMessage = response.Items[n].Error.Error()
+			response.Errors = true
+			continue
+		}
+
+		response.Items[n].SeqNo = i.seqNo
+		if docs[n] != nil {
+			response.Items[n].Version = docs[n].version
+		}
+	}
+
+	return response, nil
+}
+
+// analyzeBulk validates the operations and turns their documents into
+// Documents on a worker per cpu. Errors are set on the items
+func analyzeBulk(ctx context.Context, ops []BulkOperation, items []BulkItem) ([]*Document, error) {
+	docs := make([]*Document, len(ops))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(ops)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				items[n] = BulkItem{Type: ops[n].Type, ID: ops[n].ID}
+				docs[n], items[n].Error = ops[n].analyze()
+			}
+		}()
+	}
+
+	var err error
+	for n := range ops {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		jobs <- n
+	}
+	close(jobs)
+	wg.Wait()
+
+	return docs, err
+}
+
+// applyBulk applies a single analyzed operation, the lock must be held
+func (i *Index) applyBulk(op BulkOperation, doc *Document) error {
+	existing, ok := i.Documents[op.ID]
+
+	switch {
+	case op.Type == "index" && ok:
+		return errors.New("id already taken")
+	case (op.Type == "update" || op.Type == "delete") && !ok:
+		return errors.New("document not found")
+	}
+
+	// Missing documents can have the version they were deleted at
+	version := i.tombstoneVersion(op.ID)
+	if ok {
+		version = existing.version
+	}
+
+	if err := op.check(op.ID, existing, version); err != nil {
+		return err
+	}
+
+	if op.Type == "delete" {
+		i.seqNo++
+		if op.Version > 0 {
+			i.addTombstone(op.ID, op.Version)
+		}
+		i.removeDocument(existing)
+		return nil
+	}
+
+	// The seq no is given back if the document cannot be added
+	seqNo := i.seqNo
+	i.setVersion(doc, version, op.Version)
+
+	var err error
+	if ok {
+		err = i.replaceDocument(existing, doc)
+	} else {
+		err = i.addDocument(doc)
+	}
+	if err != nil {
+		i.seqNo = seqNo
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package gofindit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestIndex_Bulk(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	res, err := index.Bulk([]BulkOperation{
+		{Type: "index", ID: "tommy", Document: TestRecord{Name: "tommy", Score: 4}},
+		{Type: "index", ID: "billy", Document: TestRecord{Name: "billy", Score: 1}},
+		{Type: "upsert", ID: "sally", Document: TestRecord{Name: "sally", Score: 20}},
+		{Type: "upsert", ID: "jimmy", Document: TestRecord{Name: "jimmy", Score: 7}},
+		{Type: "delete", ID: "molly"},
+		{Type: "delete", ID: "nobody"},
+		{Type: "index", ID: "", Document: TestRecord{Name: "empty"}},
+		{Type: "replace", ID: "billy"},
+		{Type: "index", ID: "bad", Document: "not a struct"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Errors {
+		t.Errorf("expected errors to be true")
+	}
+
+	failed := []bool{false, true, false, false, false, true, true, true, true}
+	if len(res.Items) != len(failed) {
+		t.Fatalf("expected %d items, got %d", len(failed), len(res.Items))
+	}
+	for n, item := range res.Items {
+		if (item.Error != nil) != failed[n] {
+			t.Errorf("item %d %s %s: expected failed %v, got error %v", n, item.Type, item.ID, failed[n], item.Error)
+		}
+	}
+
+	// Errors are kept as strings in json
+	b, err := json.Marshal(res.Items[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"type":"index","id":"billy","error":"id already taken"}`; string(b) != want {
+		t.Errorf("expected %s, got %s", want, b)
+	}
+
+	tests := []struct {
+		id    string
+		score int // 0 if the document should be missing
+	}{
+		{"billy", 9},
+		{"sally", 20},
+		{"molly", 0},
+		{"tommy", 4},
+		{"jimmy", 7},
+	}
+	for _, tt := range tests {
+		doc, err := index.Get(tt.id)
+		if tt.score == 0 {
+			if err == nil {
+				t.Errorf("expected %s to be deleted", tt.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expected %s to be found, got %v", tt.id, err)
+			continue
+		}
+		if score := doc.(TestRecord).Score; score != tt.score {
+			t.Errorf("expected %s to have score %d, got %d", tt.id, tt.score, score)
+		}
+	}
+
+	// Replaced and deleted documents are gone from the doc values
+	results, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "score", Type: "range", Value: [2]int{15, 25}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected the upserted sally, got %+v", results)
+	}
+	results, err = index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "score", Type: "range", Value: [2]int{-5, 12}}},
+		SortBy: "score",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected billy, tommy and jimmy, got %+v", results)
+	}
+}
+
+func TestIndex_BulkLarge(t *testing.T) {
+	type Test struct {
+		Name string `find:"name"`
+		Num  int    `find:"num"`
+	}
+
+	ops := make([]BulkOperation, 1000)
+	for n := range ops {
+		ops[n] = BulkOperation{Type: "index", ID: fmt.Sprint(n), Document: Test{Name: fmt.Sprint("name ", n), Num: n}}
+	}
+
+	index := New()
+	res, err := index.Bulk(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Errors {
+		t.Errorf("expected no errors")
+	}
+	if len(index.Documents) != len(ops) {
+		t.Errorf("expected %d documents, got %d", len(ops), len(index.Documents))
+	}
+
+	// Documents are numbered in the order of the operations
+	for n := range ops {
+		if num := index.Documents[fmt.Sprint(n)].num; num != n {
+			t.Fatalf("expected document %d to have num %d, got %d", n, n, num)
+		}
+	}
+}
+
+func TestIndex_BulkContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	index := New()
+	_, err := index.BulkContext(ctx, []BulkOperation{{Type: "index", ID: "1", Document: TestRecord{Name: "billy"}}})
+	if err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if len(index.Documents) != 0 {
+		t.Errorf("expected no documents to be indexed")
+	}
+}
+
+func TestBulkOperation_Validate(t *testing.T) {
+	tests := []struct {
+		op      BulkOperation
+		wantErr bool
+	}{
+		{BulkOperation{Type: "index", ID: "1", Document: TestRecord{}}, false},
+		{BulkOperation{Type: "upsert", ID: "1", Document: TestRecord{}}, false},
+		{BulkOperation{Type: "delete", ID: "1"}, false},
+		{BulkOperation{Type: "index", ID: "1"}, true},
+		{BulkOperation{Type: "delete"}, true},
+		{BulkOperation{Type: "update", ID: "1"}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.op.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: expected error %v, got %v", tt.op, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package gofindit
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// newCollator creates a collator for the language tag. Primary strength
+// ignores case, accents and width, secondary ignores case and width
+// and tertiary, the default, compares all of them
+func newCollator(lang string, strength string) (*collate.Collator, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collate language %q: %v", lang, err)
+	}
+
+	var options []collate.Option
+	switch strength {
+	case "primary":
+		options = append(options, collate.Loose)
+	case "secondary":
+		options = append(options, collate.IgnoreCase, collate.IgnoreWidth)
+	case "", "tertiary":
+	default:
+		return nil, fmt.Errorf("invalid collate strength %q", strength)
+	}
+
+	return collate.New(tag, options...), nil
+}
+
+// isValidStrength checks if the provided collation strength is valid
+func isValidStrength(strength string) bool {
+	switch strength {
+	case "primary", "secondary", "tertiary":
+		return true
+	}
+	return false
+}
+
+// collateKey returns the collation key of the string. Keys
+// sort byte by byte in the order of the collators language
+func collateKey(collator *collate.Collator, buf *collate.Buffer, str string) []byte {
+	defer buf.Reset()
+	return bytes.Clone(collator.KeyFromString(buf, str))
+}
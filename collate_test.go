@@ -0,0 +1,87 @@
+package gofindit
+
+import (
+	"testing"
+)
+
+func TestIndex_Search_collate(t *testing.T) {
+	type Test struct {
+		Name    string `find:"name,sortable"`
+		English string `find:"english,collate=en"`
+		Swedish string `find:"swedish,type=keyword,collate=sv"`
+		Loose   string `find:"loose,collate=en,strength=primary"`
+	}
+
+	names := []string{"Zoë", "adam", "Émile", "eve", "Zed"}
+	index := New()
+	for _, name := range names {
+		index.Index(name, Test{Name: name, English: name, Swedish: name, Loose: name})
+	}
+
+	swedish := []string{"ola", "zed", "öst", "Åke"}
+	swedishIndex := New()
+	for _, name := range swedish {
+		swedishIndex.Index(name, Test{Swedish: name})
+	}
+
+	loose := []string{"resume", "Résumé", "résumé", "Resume", "apple"}
+	looseIndex := New()
+	for i, name := range loose {
+		looseIndex.Index(string(rune('a'+i)), Test{Loose: name})
+	}
+
+	tests := []struct {
+		name  string
+		index *Index
+		field string
+		want  []string
+	}{
+		{"Bytes", index, "name", []string{"Zed", "Zoë", "adam", "eve", "Émile"}},
+		{"English", index, "english", []string{"adam", "Émile", "eve", "Zed", "Zoë"}},
+		{"Swedish", swedishIndex, "swedish", []string{"ola", "zed", "Åke", "öst"}},
+		{"Primary ties by id", looseIndex, "loose", []string{"apple", "resume", "Résumé", "résumé", "Resume"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := tt.index.Search(SearchQuery{SortBy: tt.field, Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != len(tt.want) {
+				t.Fatalf("expected %d results, got %d", len(tt.want), len(results))
+			}
+			for i, result := range results {
+				doc := result.(Test)
+				got := map[string]string{"name": doc.Name, "english": doc.English, "swedish": doc.Swedish, "loose": doc.Loose}[tt.field]
+				if got != tt.want[i] {
+					t.Errorf("expected %s at %d, got %s", tt.want[i], i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCollator(t *testing.T) {
+	tests := []struct {
+		lang     string
+		strength string
+		wantErr  bool
+	}{
+		{"en", "", false},
+		{"de-DE", "secondary", false},
+		{"sv", "tertiary", false},
+		{"", "", true},
+		{"en", "quaternary", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"_"+tt.strength, func(t *testing.T) {
+			_, err := newCollator(tt.lang, tt.strength)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newCollator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
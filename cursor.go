@@ -0,0 +1,70 @@
+package gofindit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// cursorValue is a sortValue in a cursor
+type cursorValue struct {
+	Key     []byte  `json:"k,omitempty"`
+	Num     float64 `json:"n,omitempty"`
+	Numeric bool    `json:"t,omitempty"`
+	OK      bool    `json:"o,omitempty"`
+}
+
+// encodeCursor encodes the sort values of a hit into an opaque
+// cursor that is passed back as SearchAfter for the next page
+func encodeCursor(values []sortValue) (string, error) {
+	cursor := make([]cursorValue, len(values))
+	for n, value := range values {
+		cursor[n] = cursorValue{Key: value.key, Num: value.num, Numeric: value.numeric, OK: value.ok}
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes a cursor into the sort values of a hit
+func decodeCursor(cursor string) ([]sortValue, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search_after cursor")
+	}
+
+	var decoded []cursorValue
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid search_after cursor")
+	}
+
+	values := make([]sortValue, len(decoded))
+	for n, value := range decoded {
+		values[n] = sortValue{key: value.Key, num: value.Num, numeric: value.Numeric, ok: value.OK}
+	}
+
+	return values, nil
+}
+
+// searchAfter returns the sorted hits that come after the cursor
+func searchAfter(hits []hit, sorts []SortField, cursor string) ([]hit, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if len(after) != len(sorts) {
+		return nil, fmt.Errorf("search_after cursor does not match the sort")
+	}
+
+	for n, hit := range hits {
+		if compareSortValues(hit.sort, after, sorts) > 0 {
+			return hits[n:], nil
+		}
+	}
+
+	return hits[:0], nil
+}
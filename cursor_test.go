@@ -0,0 +1,88 @@
+package gofindit
+
+import (
+	"testing"
+)
+
+func TestIndex_SearchHits_searchAfter(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	// Page through by level desc, missing last, then id
+	var names []string
+	query := SearchQuery{Limit: 3, Sorts: []SortField{{Field: "level", Order: "desc"}}}
+	for page := 0; page < 3; page++ {
+		response, err := index.SearchHits(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.Total != 4 {
+			t.Errorf("expected total of 4, got %d", response.Total)
+		}
+		if len(response.Hits) == 0 {
+			if response.Cursor != "" {
+				t.Errorf("expected no cursor without hits")
+			}
+			break
+		}
+
+		for _, hit := range response.Hits {
+			names = append(names, hit.ID)
+		}
+		query.SearchAfter = response.Cursor
+	}
+
+	want := []string{"billy", "tommy", "sally", "molly"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected %s at %d, got %s", want[i], i, names[i])
+		}
+	}
+}
+
+func TestIndex_SearchHits_searchAfterErrors(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	response, err := index.SearchHits(SearchQuery{Limit: 1, SortBy: "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+	}{
+		{"Invalid cursor", SearchQuery{SearchAfter: "not a cursor"}},
+		{"Different sort", SearchQuery{SearchAfter: response.Cursor, Sorts: []SortField{{Field: "team"}, {Field: "name"}}}},
+		{"With skip", SearchQuery{SearchAfter: response.Cursor, SortBy: "name", Skip: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := index.SearchHits(tt.query); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	values := []sortValue{{key: []byte("billy"), ok: true}, {num: 2.5, numeric: true, ok: true}, {}}
+
+	cursor, err := encodeCursor(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorts := []SortField{{Field: "a"}, {Field: "b"}, {Field: "c"}}
+	if len(decoded) != len(values) || compareSortValues(values, decoded, sorts) != 0 {
+		t.Errorf("expected %+v, got %+v", values, decoded)
+	}
+}
@@ -2,7 +2,10 @@ package gofindit
 
 import (
 	"fmt"
+	"maps"
 	"math/rand/v2"
+	"slices"
+	"testing"
 	"time"
 )
 
@@ -135,17 +138,39 @@ var funnyPetToys = []string{
 
 func generateTestDocs() {
 	// Generate a bunch of test documents
-	for i := 0; i < 10000; i++ {
+	ops := make([]BulkOperation, 10000)
+	for i := range ops {
 		id, doc := generateDoc()
+		ops[i] = BulkOperation{Type: "index", ID: id, Document: doc}
+	}
 
-		err := TestIndex.Index(id, doc)
-		if err != nil {
-			fmt.Println(err)
+	res, err := TestIndex.Bulk(ops)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, item := range res.Items {
+		if item.Error != nil {
+			fmt.Println(item.Error)
 			return
 		}
 	}
 }
 
+// newTestIndex indexes the documents into the index in id order
+// so tests get the same internal numbers on every run
+func newTestIndex(t *testing.T, index *Index, docs map[string]any) *Index {
+	t.Helper()
+
+	for _, id := range slices.Sorted(maps.Keys(docs)) {
+		if err := index.Index(id, docs[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return index
+}
+
 func generateDoc() (string, TestData) {
 	id := fmt.Sprintf("%d", randSource.Uint64())
 	name := fmt.Sprintf("%s %s", firstnames[randSource.IntN(len(firstnames)-1)], lastnames[randSource.IntN(len(lastnames)-1)])
@@ -165,7 +190,7 @@ func generateDoc() (string, TestData) {
 	pets := make([]TestPet, randSource.IntN(3))
 	for j := 0; j < len(pets); j++ {
 		// Grab stuff
-		petType := petTypes[randSource.IntN(len(pets)-1)]
+		petType := petTypes[randSource.IntN(len(petTypes))]
 		petBreed := petBreeds[petType][randSource.IntN(len(petBreeds[petType])-1)]
 		petName := petNames[petType][randSource.IntN(len(petNames[petType])-1)]
 		petAge := randSource.IntN(15)
@@ -188,6 +213,7 @@ func generateDoc() (string, TestData) {
 		Name:      name,
 		Age:       age,
 		Hobbies:   myhobbies,
+		Pets:      pets,
 		Bio:       bio,
 		isStudent: isStudent,
 		Birthday:  birthday,
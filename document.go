@@ -5,13 +5,20 @@ import (
 )
 
 type Document struct {
+	ID       string
 	Original any
 	Fields   map[string]fields.Field
+	Nulls    map[string]bool // Fields that were a nil pointer, interface or slice
+
+	num     int // Internal number in the index, used for doc values
+	version int64
+	seqNo   int64
+	options map[string]tagOptions
 }
 
 func NewDoc(doc any) (*Document, error) {
 	// Get structure of the document
-	fields, err := getStructure(doc, "")
+	structure, err := newStructure(doc, "")
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +26,9 @@ func NewDoc(doc any) (*Document, error) {
 	// Create a new document
 	document := Document{
 		Original: doc,
-		Fields:   fields,
+		Fields:   structure.fields,
+		Nulls:    structure.nulls,
+		options:  structure.options,
 	}
 
 	return &document, nil
@@ -29,3 +38,70 @@ func (d *Document) GetField(field string) (fields.Field, bool) {
 	val, ok := d.Fields[field]
 	return val, ok
 }
+
+// Version returns the version of the document, it goes up every
+// time the document is written unless an external version is used
+func (d *Document) Version() int64 {
+	return d.version
+}
+
+// SeqNo returns the seq no of the write that indexed the document,
+// every write to the index gets the next seq no
+func (d *Document) SeqNo() int64 {
+	return d.seqNo
+}
+
+// IsNull returns true if the field was a nil value in the original document
+func (d *Document) IsNull(field string) bool {
+	return d.Nulls[field]
+}
+
+// score returns the average score of the queried fields, each is its
+// relevance times the boost from its tag, which defaults to 1, so a
+// boosted field counts for more of the score than the other fields
+func (d *Document) score(queries []SearchQueryField) (float64, error) {
+	if len(queries) == 0 {
+		return 1, nil
+	}
+
+	total := 0.0
+	for _, query := range queries {
+		relevance, err := d.relevance(query)
+		if err != nil {
+			return 0, err
+		}
+
+		boost := 1.0
+		if options, ok := d.options[query.Field]; ok {
+			boost = options.Boost
+		}
+		total += boost * relevance
+	}
+
+	return total / float64(len(queries)), nil
+}
+
+// relevance returns how well the field matched the query. Match queries
+// on fields that can score, like text, score how well the value matches
+// and the rest are 1 as the document only gets here if they matched
+func (d *Document) relevance(query SearchQueryField) (float64, error) {
+	if query.Type != "match" {
+		return 1, nil
+	}
+
+	field, ok := d.GetField(query.Field)
+	if !ok {
+		return 1, nil
+	}
+	scorer, ok := field.(fields.Scorer)
+	if !ok {
+		return 1, nil
+	}
+
+	// Values the field cannot search were matched as another type
+	searchBytes, err := field.ToSearchBytes(query.Value)
+	if err != nil {
+		return 1, nil
+	}
+	return scorer.Score(searchBytes)
+}
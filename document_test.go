@@ -21,12 +21,12 @@ func ExampleNewDoc() {
 		return
 	}
 
-	fmt.Println(document.Fields)
+	fmt.Println(document.Fields["Name"].Value(), document.Fields["Age"].Value())
 
-	// Output: map[Age:{int 10 []} Name:{string Test [test]}]
+	// Output: Test 10
 }
 
-func ExampleDocument_GetFieldValue() {
+func ExampleDocument_GetField() {
 	type Test struct {
 		Name string `find:"name"`
 		Age  int    `find:"age"`
@@ -41,12 +41,12 @@ func ExampleDocument_GetFieldValue() {
 	// Create a new document
 	document, _ := NewDoc(doc)
 
-	nameField, _ := document.GetField("name")
-	fmt.Println(nameField.Value())
+	nameField, found := document.GetField("name")
+	fmt.Println(nameField.Value(), found)
 
-	valueField, _ := document.GetField("age")
-	fmt.Println(valueField.Value())
+	valueField, found := document.GetField("age")
+	fmt.Println(valueField.Value(), found)
 
-	// Output: test true
+	// Output: Test true
 	// 10 true
 }
@@ -0,0 +1,227 @@
+package gofindit
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/brianvoe/gofindit/fields"
+	"golang.org/x/text/collate"
+)
+
+// docValues is a column of a fields values for every document, keyed
+// by the documents internal number. Keys sort byte by byte in the order
+// of the field type so sorting and range filters never need the original
+// documents or any string formatting. Fields in arrays of structs, like
+// pets[0].age and pets[1].age, are kept together in one pets.age column
+// and multi value fields, like a []string, have a value for each item.
+// Only documents with values are kept so removed documents free theirs
+type docValues struct {
+	typ   string
+	field fields.Field // First field indexed, used to encode query values
+
+	// Text and keywords are keyed by collation key if there is a collator
+	collator *collate.Collator
+	buf      collate.Buffer
+
+	values map[int][]docValue
+}
+
+// docValue is a single value of a document in a column
+type docValue struct {
+	key  []byte
+	num  float64 // Only set for numeric types
+	term string
+}
+
+// newDocValues creates an empty column for the type of the field.
+// Text and keywords use a collator if the options have a collate
+func newDocValues(field fields.Field, options tagOptions) *docValues {
+	dv := &docValues{typ: field.Type(), field: field, values: make(map[int][]docValue)}
+
+	if options.Collate != "" && (dv.typ == fields.TextType || dv.typ == fields.KeywordType) {
+		// The tag options were already validated
+		dv.collator, _ = newCollator(options.Collate, options.Strength)
+	}
+
+	return dv
+}
+
+// add appends the values of the field to the values of the document,
+// one for each item of a multi value field. Fields of another type or
+// that cannot be keyed are left out
+func (dv *docValues) add(num int, field fields.Field) {
+	if field.Type() != dv.typ {
+		return
+	}
+
+	items := fieldItems(field)
+	for _, item := range items {
+		key, err := docValueKey(field, item)
+		if err != nil {
+			continue
+		}
+		if dv.collator != nil {
+			key = collateKey(dv.collator, &dv.buf, string(key))
+		}
+
+		value := docValue{key: key, term: fmt.Sprint(item)}
+		if len(items) == 1 {
+			value.num, _ = docValueNum(field)
+		}
+		dv.values[num] = append(dv.values[num], value)
+	}
+}
+
+// fieldItems returns each item of a multi value field,
+// like a []string, or the value of any other field
+func fieldItems(field fields.Field) []any {
+	value := reflect.ValueOf(field.Value())
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.String {
+		return []any{field.Value()}
+	}
+
+	items := make([]any, value.Len())
+	for n := range items {
+		items[n] = value.Index(n).String()
+	}
+	return items
+}
+
+// get returns the values of the document, or nil if it is missing
+func (dv *docValues) get(num int) []docValue {
+	return dv.values[num]
+}
+
+// numeric returns true if the column has numbers for aggregations
+func (dv *docValues) numeric() bool {
+	switch dv.typ {
+	case fields.NumberType, fields.DateType, fields.DurationType, fields.DecimalType, fields.BooleanType:
+		return true
+	}
+	return false
+}
+
+// key encodes a query value the same way as the values in the column
+func (dv *docValues) key(val any) ([]byte, error) {
+	return docValueKey(dv.field, val)
+}
+
+// docValueKey encodes the value with the field into a key that sorts
+// byte by byte. Text sorts by its string and types without an order,
+// like geo points and vectors, cannot be keyed
+func docValueKey(field fields.Field, val any) ([]byte, error) {
+	switch field.Type() {
+	case fields.GeoType, fields.VectorType:
+		return nil, fmt.Errorf("%s fields cannot be sorted", field.Type())
+	case fields.TextType:
+		return []byte(fmt.Sprint(val)), nil
+	}
+
+	return field.ToSearchBytes(val)
+}
+
+// docValueNum returns the field as a float64. Dates
+// are unix milliseconds and durations are nanoseconds
+func docValueNum(field fields.Field) (float64, bool) {
+	switch f := field.(type) {
+	case *fields.Num:
+		num, err := toFloat64(f.Value())
+		return num, err == nil
+	case *fields.Date:
+		return float64(f.ToDateTime().UnixMilli()), true
+	case *fields.Duration:
+		return float64(f.Duration()), true
+	case *fields.Decimal:
+		num, err := strconv.ParseFloat(f.String(), 64)
+		return num, err == nil
+	case *fields.Bool:
+		if f.Value() == true {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// indexDocValues adds the fields of the document to the columns. Text
+// is only kept when it is sortable, other types with an order always are
+func (i *Index) indexDocValues(doc *Document) {
+	for name, field := range doc.Fields {
+		column := columnName(name)
+
+		dv, ok := i.docValues[column]
+		if !ok {
+			if field.Type() == fields.TextType && !doc.options[name].Sortable {
+				continue
+			}
+			if _, err := docValueKey(field, field.Value()); err != nil {
+				continue
+			}
+
+			dv = newDocValues(field, doc.options[name])
+			i.docValues[column] = dv
+		}
+
+		dv.add(doc.num, field)
+	}
+}
+
+// removeDocValues clears the values of the document from the columns
+func (i *Index) removeDocValues(doc *Document) {
+	for name := range doc.Fields {
+		if dv, ok := i.docValues[columnName(name)]; ok {
+			delete(dv.values, doc.num)
+		}
+	}
+}
+
+// columnName removes the array indexes from a field name
+// so pets[0].age and pets[1].age are both pets.age
+func columnName(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+	return arrayIndexRegex.ReplaceAllString(name, "")
+}
+
+var arrayIndexRegex = regexp.MustCompile(`\[\d+\]`)
+
+// docFields returns the field of the document, or every field
+// in an array of structs that has the column name
+func docFields(doc *Document, name string) []fields.Field {
+	if field, ok := doc.GetField(name); ok {
+		return []fields.Field{field}
+	}
+
+	var found []fields.Field
+	for fieldName, field := range doc.Fields {
+		if strings.Contains(fieldName, "[") && columnName(fieldName) == name {
+			found = append(found, field)
+		}
+	}
+	return found
+}
+
+// columnFor returns the column for the field. Fields without one,
+// like text that is not sortable, get a column of just the documents
+func (i *Index) columnFor(field string, docs []*Document) *docValues {
+	if dv, ok := i.docValues[field]; ok {
+		return dv
+	}
+
+	var dv *docValues
+	for _, doc := range docs {
+		for _, f := range docFields(doc, field) {
+			if dv == nil {
+				dv = newDocValues(f, doc.options[field])
+			}
+			dv.add(doc.num, f)
+		}
+	}
+
+	return dv
+}
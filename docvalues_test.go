@@ -0,0 +1,148 @@
+package gofindit
+
+import (
+	"testing"
+	"time"
+)
+
+type TestRecord struct {
+	Name    string    `find:"name,sortable"`
+	Score   int       `find:"score"`
+	Born    time.Time `find:"born,type=date,precision=s"`
+	Note    string    `find:"note"`
+	Balance *float64  `find:"balance"`
+}
+
+func newTestRecordIndex(t *testing.T) *Index {
+	t.Helper()
+
+	balance := -12.5
+	return newTestIndex(t, New(), map[string]any{
+		"billy": TestRecord{Name: "billy", Score: 9, Born: time.Date(1965, 4, 2, 0, 0, 0, 0, time.UTC), Note: "b"},
+		"sally": TestRecord{Name: "sally", Score: 10, Born: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), Note: "a", Balance: &balance},
+		"molly": TestRecord{Name: "molly", Score: -3, Born: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), Note: "c"},
+	})
+}
+
+func TestIndex_docValues(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	// Text is only kept when sortable
+	if _, ok := index.docValues["name"]; !ok {
+		t.Errorf("expected sortable text field name to have doc values")
+	}
+	if _, ok := index.docValues["note"]; ok {
+		t.Errorf("expected text field note to not have doc values")
+	}
+
+	dv := index.docValues["score"]
+	if dv == nil || dv.typ != "n" {
+		t.Fatalf("expected number doc values for score, got %+v", dv)
+	}
+	doc := index.Documents["sally"]
+	if values := dv.get(doc.num); len(values) != 1 || values[0].num != 10 {
+		t.Errorf("expected score 10 for sally, got %+v", values)
+	}
+
+	// Nil pointers are missing from the column
+	balance := index.docValues["balance"]
+	if values := balance.get(index.Documents["billy"].num); values != nil {
+		t.Errorf("expected nil balance to be missing")
+	}
+}
+
+func TestIndex_docValues_removed(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	// Updates give the document a new number, the old values are freed
+	for n := 0; n < 10; n++ {
+		if err := index.Update("billy", TestRecord{Name: "billy", Score: n}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := index.Delete("molly"); err != nil {
+		t.Fatal(err)
+	}
+
+	if values := index.docValues["score"].values; len(values) != 2 {
+		t.Errorf("expected score values for 2 documents, got %d", len(values))
+	}
+}
+
+func TestIndex_Search_sortDocValues(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	tests := []struct {
+		sortBy string
+		sort   string
+		want   []string
+	}{
+		{"score", "asc", []string{"molly", "billy", "sally"}},
+		{"score", "desc", []string{"sally", "billy", "molly"}},
+		{"born", "asc", []string{"billy", "molly", "sally"}},
+		{"name", "asc", []string{"billy", "molly", "sally"}},
+		{"note", "desc", []string{"molly", "billy", "sally"}},
+		{"balance", "desc", []string{"sally", "billy", "molly"}},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Sort:   tt.sort,
+			SortBy: tt.sortBy,
+			Fields: []SearchQueryField{{Field: tt.sortBy, Type: "exists"}, {Field: "score", Type: "range", Value: []int{-100, 100}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Balance only exists on sally
+		want := tt.want
+		if tt.sortBy == "balance" {
+			want = want[:1]
+		}
+		if len(results) != len(want) {
+			t.Errorf("expected %d results sorting by %s, got %d", len(want), tt.sortBy, len(results))
+			continue
+		}
+		for i, result := range results {
+			if result.(TestRecord).Name != want[i] {
+				t.Errorf("expected %s at %d sorting by %s %s, got %s", want[i], i, tt.sortBy, tt.sort, result.(TestRecord).Name)
+			}
+		}
+	}
+}
+
+func TestIndex_Search_rangeDocValues(t *testing.T) {
+	index := newTestRecordIndex(t)
+
+	tests := []struct {
+		field string
+		value any
+		want  int
+	}{
+		{"score", []int{9, 10}, 2},
+		{"score", []float64{-5, 0}, 1},
+		{"born", []time.Time{time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)}, 2},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: tt.field, Type: "range", Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != tt.want {
+			t.Errorf("expected %d results for %s in %v, got %d", tt.want, tt.field, tt.value, len(results))
+		}
+	}
+
+	// Range values the field cannot encode are an error
+	_, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "score", Type: "range", Value: []any{1, "ten"}}},
+	})
+	if err == nil {
+		t.Errorf("expected range with a string on a number field to error")
+	}
+}
@@ -16,6 +16,10 @@ type Bool struct {
 }
 
 func NewBool(config map[string]any) (Field, error) {
+	if err := checkConfig("bool", config); err != nil {
+		return nil, err
+	}
+
 	return &Bool{}, nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,40 +13,173 @@ func init() {
 	SetField("date", NewDate)
 }
 
+// dateLayouts are the named layouts that can be used in the layouts config
+var dateLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"date":        time.DateOnly,
+	"datetime":    time.DateTime,
+}
+
+// defaultDateLayouts are tried in order when parsing strings. Epochs are
+// opt in through layouts so numbers like "2023" are not read as a date
+var defaultDateLayouts = []string{"rfc3339nano", "datetime", "date"}
+
 type Date struct {
 	v           any // original value
 	value       []byte
 	granularity string
+	location    *time.Location // nil keeps the location of the value
+	precision   string
+	layouts     []string
 }
 
 // NewDate creates a new Date with the given configuration
+//
+//   - granularity: none, second, minute, hour, day, week (ISO), month, quarter or year.
+//     Defaults to day, or none if precision is set
+//   - precision: s, ms, us or ns stored, defaults to s
+//   - timezone: IANA name or *time.Location dates are converted to before truncating
+//   - layouts: []string or pipe separated string of time layouts, named layouts
+//     (rfc3339, rfc3339nano, rfc1123, date, datetime) or epoch_millis and
+//     epoch_second, used to parse strings
 func NewDate(config map[string]any) (Field, error) {
-	// Default granularity is "day"
-	granularity := "day"
+	if err := checkConfig("date", config, "precision", "granularity", "timezone", "layouts"); err != nil {
+		return nil, err
+	}
+
+	d := &Date{granularity: "day", precision: "s", layouts: defaultDateLayouts}
+
+	if val, ok := config["precision"]; ok {
+		if prec, ok := val.(string); ok && isValidPrecision(prec) {
+			d.precision = prec
+			d.granularity = "none"
+		} else {
+			return nil, fmt.Errorf("invalid precision value")
+		}
+	}
+
 	if val, ok := config["granularity"]; ok {
 		if gran, ok := val.(string); ok && isValidGranularity(gran) {
-			granularity = gran
+			d.granularity = gran
 		} else {
 			return nil, fmt.Errorf("invalid granularity value")
 		}
 	}
 
-	return &Date{granularity: granularity}, nil
+	if val, ok := config["timezone"]; ok {
+		switch tz := val.(type) {
+		case *time.Location:
+			d.location = tz
+		case string:
+			location, err := time.LoadLocation(tz)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timezone value: %v", err)
+			}
+			d.location = location
+		default:
+			return nil, fmt.Errorf("invalid timezone value")
+		}
+	}
+
+	if val, ok := config["layouts"]; ok {
+		switch layouts := val.(type) {
+		case []string:
+			d.layouts = layouts
+		case string:
+			d.layouts = strings.Split(layouts, "|")
+		default:
+			return nil, fmt.Errorf("invalid layouts value")
+		}
+	}
+
+	return d, nil
 }
 
 // dateToSearchBytes adjusts the provided time.Time value according to the specified granularity and converts it to bytes
 func dateToSearchBytes(date any, granularity string) ([]byte, error) {
-	dateVal, ok := date.(time.Time)
-	if !ok {
-		return nil, fmt.Errorf("Date requires a time.Time value")
+	d := &Date{granularity: granularity, precision: "s", layouts: defaultDateLayouts}
+	return d.dateToSearchBytes(date)
+}
+
+// dateToSearchBytes parses the value, adjusts it to the timezone
+// and granularity and converts it to bytes at the precision
+func (d *Date) dateToSearchBytes(date any) ([]byte, error) {
+	dateVal, err := d.toTime(date)
+	if err != nil {
+		return nil, err
 	}
 
-	adjustedDate := adjustDateToGranularity(dateVal, granularity)
-	buf := new(bytes.Buffer)
-	if err := binary.Write(buf, binary.BigEndian, adjustedDate.Unix()); err != nil {
-		return nil, fmt.Errorf("error converting date value to bytes: %v", err)
+	if d.location != nil {
+		dateVal = dateVal.In(d.location)
 	}
-	return buf.Bytes(), nil
+	adjustedDate := adjustDateToGranularity(dateVal, d.granularity)
+
+	var stamp int64
+	switch d.precision {
+	case "ms":
+		stamp = adjustedDate.UnixMilli()
+	case "us", "µs":
+		stamp = adjustedDate.UnixMicro()
+	case "ns":
+		stamp = adjustedDate.UnixNano()
+	default:
+		stamp = adjustedDate.Unix()
+	}
+
+	// Flip the sign bit so dates before 1970 sort before later ones
+	return binary.BigEndian.AppendUint64(nil, uint64(stamp)^1<<63), nil
+}
+
+// toTime converts a time.Time or a string in one of the layouts to a time.Time
+func (d *Date) toTime(date any) (time.Time, error) {
+	switch v := date.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, fmt.Errorf("Date requires a non nil time")
+		}
+		return *v, nil
+	case string:
+		return d.parse(v)
+	}
+
+	return time.Time{}, fmt.Errorf("Date requires a time.Time value")
+}
+
+// parse tries each layout in order. Layouts without a zone
+// are parsed in the timezone, or UTC if there isnt one
+func (d *Date) parse(str string) (time.Time, error) {
+	location := d.location
+	if location == nil {
+		location = time.UTC
+	}
+
+	str = strings.TrimSpace(str)
+	for _, layout := range d.layouts {
+		switch layout {
+		case "epoch_millis", "epoch_second":
+			stamp, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				continue
+			}
+			if layout == "epoch_millis" {
+				return time.UnixMilli(stamp).In(location), nil
+			}
+			return time.Unix(stamp, 0).In(location), nil
+		}
+
+		if named, ok := dateLayouts[layout]; ok {
+			layout = named
+		}
+		if t, err := time.ParseInLocation(layout, str, location); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("date %q does not match any layout", str)
 }
 
 func (d *Date) Type() string {
@@ -55,24 +190,45 @@ func (d *Date) Value() any {
 	return d.v
 }
 
+// ToDateTime returns the stored date in the
+// timezone, or the local timezone if there isnt one
 func (d *Date) ToDateTime() time.Time {
-	var timestamp int64
-	buf := bytes.NewReader(d.value)
-	binary.Read(buf, binary.BigEndian, &timestamp)
-	return time.Unix(timestamp, 0)
+	var stamp int64
+	if len(d.value) == 8 {
+		stamp = int64(binary.BigEndian.Uint64(d.value) ^ 1<<63)
+	}
+
+	var t time.Time
+	switch d.precision {
+	case "ms":
+		t = time.UnixMilli(stamp)
+	case "us", "µs":
+		t = time.UnixMicro(stamp)
+	case "ns":
+		t = time.Unix(0, stamp)
+	default:
+		t = time.Unix(stamp, 0)
+	}
+
+	if d.location != nil {
+		t = t.In(d.location)
+	}
+	return t
 }
 
 func (d *Date) Process(dateVal any) error {
-	bytes, err := dateToSearchBytes(dateVal, d.granularity)
+	bytes, err := d.dateToSearchBytes(dateVal)
 	if err != nil {
 		return err
 	}
+	// Set original value
+	d.v = dateVal
 	d.value = bytes
 	return nil
 }
 
 func (d *Date) ToSearchBytes(val any) ([]byte, error) {
-	return dateToSearchBytes(val, d.granularity)
+	return d.dateToSearchBytes(val)
 }
 
 func (d *Date) Search(searchValue []byte) (bool, error) {
@@ -89,8 +245,14 @@ func adjustDateToGranularity(t time.Time, granularity string) time.Time {
 	switch granularity {
 	case "year":
 		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	case "quarter":
+		return time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, t.Location())
 	case "month":
 		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case "week":
+		// ISO weeks start on monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
 	case "day":
 		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 	case "hour":
@@ -107,7 +269,16 @@ func adjustDateToGranularity(t time.Time, granularity string) time.Time {
 // isValidGranularity checks if the provided granularity string is valid
 func isValidGranularity(granularity string) bool {
 	switch granularity {
-	case "year", "month", "day", "hour", "minute", "second":
+	case "year", "quarter", "month", "week", "day", "hour", "minute", "second", "none":
+		return true
+	}
+	return false
+}
+
+// isValidPrecision checks if the provided precision string is valid
+func isValidPrecision(precision string) bool {
+	switch precision {
+	case "s", "ms", "us", "µs", "ns":
 		return true
 	}
 	return false
@@ -1,8 +1,6 @@
 package fields
 
 import (
-	"bytes"
-	"encoding/binary"
 	"testing"
 	"time"
 )
@@ -51,11 +49,8 @@ func TestDate_Process(t *testing.T) {
 			fieldDate := field.(*Date)
 			fieldDate.Process(tc.date)
 
-			// Convert the stored bytes back to a time.Time value, explicitly using UTC
-			var timestamp int64
-			buf := bytes.NewReader(fieldDate.value)
-			binary.Read(buf, binary.BigEndian, &timestamp)
-			storedDate := time.Unix(timestamp, 0).In(location) // Use the same location for comparison
+			// Convert the stored bytes back to a time.Time value
+			storedDate := fieldDate.ToDateTime().In(location) // Use the same location for comparison
 
 			if storedDate.Year() != tc.wantYear || storedDate.Month() != tc.wantMonth || storedDate.Day() != tc.wantDay {
 				t.Errorf("Process() with %v granularity, got %v, want %v-%v-%v",
@@ -171,3 +166,131 @@ func TestDate_SearchRange(t *testing.T) {
 		})
 	}
 }
+
+func TestDate_granularity(t *testing.T) {
+	tests := []struct {
+		granularity string
+		date        time.Time
+		want        time.Time
+	}{
+		// 2023-03-16 is a thursday in ISO week 11 which starts monday 2023-03-13
+		{"week", time.Date(2023, 3, 16, 12, 0, 0, 0, time.UTC), time.Date(2023, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2023, 3, 19, 12, 0, 0, 0, time.UTC), time.Date(2023, 3, 13, 0, 0, 0, 0, time.UTC)}, // Sunday
+		{"week", time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC)},
+		{"quarter", time.Date(2023, 3, 16, 12, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"quarter", time.Date(2023, 8, 2, 12, 0, 0, 0, time.UTC), time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"none", time.Date(2023, 8, 2, 12, 30, 15, 0, time.UTC), time.Date(2023, 8, 2, 12, 30, 15, 0, time.UTC)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.granularity+" "+tc.date.String(), func(t *testing.T) {
+			got := adjustDateToGranularity(tc.date, tc.granularity)
+			if !got.Equal(tc.want) {
+				t.Errorf("adjustDateToGranularity() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDate_timezone(t *testing.T) {
+	// 2023-03-15 02:00 in UTC is still 2023-03-14 in New York
+	date := time.Date(2023, 3, 15, 2, 0, 0, 0, time.UTC)
+
+	field, err := NewDate(map[string]any{"timezone": "America/New_York"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	field.Process(date)
+
+	got := field.(*Date).ToDateTime()
+	if got.Day() != 14 || got.Location().String() != "America/New_York" {
+		t.Errorf("expected 2023-03-14 in New York, got %v", got)
+	}
+
+	// Dates without a zone are parsed in the timezone
+	searchBytes, err := field.ToSearchBytes("2023-03-14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match, _ := field.Search(searchBytes); !match {
+		t.Errorf("expected 2023-03-14 to match")
+	}
+
+	if _, err := NewDate(map[string]any{"timezone": "Nowhere/Special"}); err == nil {
+		t.Errorf("expected invalid timezone to error")
+	}
+}
+
+func TestDate_precision(t *testing.T) {
+	date := time.Date(2023, 3, 15, 2, 0, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		precision string
+		want      time.Time
+	}{
+		{"s", time.Date(2023, 3, 15, 2, 0, 0, 0, time.UTC)},
+		{"ms", time.Date(2023, 3, 15, 2, 0, 0, 123000000, time.UTC)},
+		{"us", time.Date(2023, 3, 15, 2, 0, 0, 123456000, time.UTC)},
+		{"ns", date},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.precision, func(t *testing.T) {
+			field, err := NewDate(map[string]any{"precision": tc.precision})
+			if err != nil {
+				t.Fatal(err)
+			}
+			field.Process(date)
+
+			if got := field.(*Date).ToDateTime(); !got.Equal(tc.want) {
+				t.Errorf("ToDateTime() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := NewDate(map[string]any{"precision": "minutes"}); err == nil {
+		t.Errorf("expected invalid precision to error")
+	}
+}
+
+func TestDate_layouts(t *testing.T) {
+	want := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		layouts any
+		value   string
+		wantErr bool
+	}{
+		{"Default RFC3339", nil, "2023-03-15T10:30:00Z", false},
+		{"Default date", nil, "2023-03-15", false},
+		{"Default no epoch millis", nil, "1678838400000", true},
+		{"Epoch millis", "date|epoch_millis", "1678838400000", false},
+		{"Custom", "02/01/2006", "15/03/2023", false},
+		{"Named pipe separated", "rfc1123|date", "2023-03-15", false},
+		{"Epoch second", []string{"epoch_second"}, "1678838400", false},
+		{"No match", "date", "March 15th", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := map[string]any{"timezone": "UTC"}
+			if tc.layouts != nil {
+				config["layouts"] = tc.layouts
+			}
+
+			field, err := NewDate(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = field.Process(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && !field.(*Date).ToDateTime().Equal(want) {
+				t.Errorf("ToDateTime() = %v, want %v", field.(*Date).ToDateTime(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	SetField("decimal", NewDecimal)
+}
+
+// Decimal stores an exact decimal of any precision in an encoding
+// that sorts byte by byte in numeric order. Values that are equal,
+// like 1.5 and 1.50, have the same bytes
+type Decimal struct {
+	v     any // original value
+	str   string
+	value []byte
+}
+
+// NewDecimal creates a new Decimal that will do an exact and range search
+func NewDecimal(config map[string]any) (Field, error) {
+	if err := checkConfig("decimal", config); err != nil {
+		return nil, err
+	}
+
+	return &Decimal{}, nil
+}
+
+// maxDecimalZeros is how many zeros String adds
+// before it switches to scientific notation
+const maxDecimalZeros = 20
+
+// decimal is a number normalized to 0.digits x 10^exp with no leading
+// or trailing zeros in the digits. The exp fits in an int32 so it can
+// be encoded in 4 bytes
+type decimal struct {
+	neg    bool
+	digits string
+	exp    int
+}
+
+// parseDecimal parses a decimal string like "-123.4500" or "1.5e-3"
+func parseDecimal(str string) (decimal, error) {
+	var d decimal
+	s := strings.TrimSpace(str)
+
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		d.neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	// Split off the exponent
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exp, err = strconv.Atoi(s[i+1:])
+		if err != nil {
+			return d, fmt.Errorf("invalid decimal %q", str)
+		}
+		if exp < math.MinInt32 || exp > math.MaxInt32 {
+			return d, fmt.Errorf("decimal %q exponent out of range", str)
+		}
+		s = s[:i]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return d, fmt.Errorf("invalid decimal %q", str)
+	}
+	for _, c := range whole + frac {
+		if c < '0' || c > '9' {
+			return d, fmt.Errorf("invalid decimal %q", str)
+		}
+	}
+
+	// Normalize so the digits start right after the decimal point
+	digits := strings.TrimLeft(whole+frac, "0")
+	d.exp = exp + len(whole) - (len(whole+frac) - len(digits))
+	d.digits = strings.TrimRight(digits, "0")
+
+	// Zero has no sign or exponent
+	if d.digits == "" {
+		return decimal{}, nil
+	}
+	if d.exp < math.MinInt32 || d.exp > math.MaxInt32 {
+		return d, fmt.Errorf("decimal %q exponent out of range", str)
+	}
+
+	return d, nil
+}
+
+// String returns the decimal without an exponent unless
+// that needs more than maxDecimalZeros zeros, like 1e-30
+func (d decimal) String() string {
+	if d.digits == "" {
+		return "0"
+	}
+
+	var s string
+	switch {
+	case d.exp < -maxDecimalZeros || d.exp > len(d.digits)+maxDecimalZeros:
+		s = d.digits[:1]
+		if len(d.digits) > 1 {
+			s += "." + d.digits[1:]
+		}
+		s += "e" + strconv.Itoa(d.exp-1)
+	case d.exp <= 0:
+		s = "0." + strings.Repeat("0", -d.exp) + d.digits
+	case d.exp >= len(d.digits):
+		s = d.digits + strings.Repeat("0", d.exp-len(d.digits))
+	default:
+		s = d.digits[:d.exp] + "." + d.digits[d.exp:]
+	}
+
+	if d.neg {
+		return "-" + s
+	}
+	return s
+}
+
+// bytes encodes the decimal as a sign byte, the exponent and the
+// digits followed by a terminator. Negatives have everything after
+// the sign byte inverted so larger magnitudes sort first
+func (d decimal) bytes() []byte {
+	if d.digits == "" {
+		return []byte{0x80}
+	}
+
+	b := make([]byte, 5, 6+len(d.digits))
+	b[0] = 0xc0
+	binary.BigEndian.PutUint32(b[1:], uint32(int32(d.exp))^(1<<31))
+	for _, c := range d.digits {
+		// Digits are 1 to 10 so the 0 terminator sorts shorter values first
+		b = append(b, byte(c-'0')+1)
+	}
+	b = append(b, 0)
+
+	if d.neg {
+		b[0] = 0x40
+		for i := 1; i < len(b); i++ {
+			b[i] = ^b[i]
+		}
+	}
+
+	return b
+}
+
+// toDecimal converts a string, int, uint, float, *big.Int or *big.Float into a decimal
+func toDecimal(val any) (decimal, error) {
+	switch v := val.(type) {
+	case string:
+		return parseDecimal(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return parseDecimal(fmt.Sprint(v))
+	case float32:
+		return parseDecimal(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		return parseDecimal(strconv.FormatFloat(v, 'g', -1, 64))
+	case *big.Int:
+		if v != nil {
+			return parseDecimal(v.String())
+		}
+	case *big.Float:
+		if v != nil {
+			return parseDecimal(v.Text('g', -1))
+		}
+	default:
+		return decimal{}, fmt.Errorf("unsupported type for Decimal: %T", val)
+	}
+
+	return decimal{}, fmt.Errorf("Decimal requires a non nil value")
+}
+
+func decimalToSearchBytes(val any) ([]byte, error) {
+	d, err := toDecimal(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.bytes(), nil
+}
+
+func (d *Decimal) Type() string {
+	return DecimalType
+}
+
+func (d *Decimal) Value() any {
+	return d.v
+}
+
+// String returns the normalized decimal, like 1.5 for "001.50"
+func (d *Decimal) String() string {
+	return d.str
+}
+
+// Process converts the decimal to bytes and stores it in the Decimal struct
+func (d *Decimal) Process(val any) error {
+	// Set original value
+	d.v = val
+
+	dec, err := toDecimal(val)
+	if err != nil {
+		return fmt.Errorf("failed to process decimal value: %v", err)
+	}
+	d.str = dec.String()
+	d.value = dec.bytes()
+	return nil
+}
+
+func (d *Decimal) ToSearchBytes(val any) ([]byte, error) {
+	return decimalToSearchBytes(val)
+}
+
+// Compare returns -1, 0 or 1 comparing the decimals in numeric order
+func (d *Decimal) Compare(other *Decimal) int {
+	return bytes.Compare(d.value, other.value)
+}
+
+// Search compares the given byte slice directly with the Decimal's stored byte slice
+func (d *Decimal) Search(val []byte) (bool, error) {
+	return bytes.Equal(d.value, val), nil
+}
+
+// SearchRange checks if the stored value is within the given range [min, max]
+func (d *Decimal) SearchRange(min, max []byte) (bool, error) {
+	return bytes.Compare(d.value, min) >= 0 && bytes.Compare(d.value, max) <= 0, nil
+}
@@ -0,0 +1,129 @@
+package fields
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestDecimal_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     any
+		want      string
+		expectErr bool
+	}{
+		{"String", "123.4500", "123.45", false},
+		{"Leading zeros", "-001.50", "-1.5", false},
+		{"Small", "0.000012", "0.000012", false},
+		{"Exponent", "1.5e-3", "0.0015", false},
+		{"Large", "123456789012345678901234567890.1", "123456789012345678901234567890.1", false},
+		{"Zero", "-0.000", "0", false},
+		{"Int", 1200, "1200", false},
+		{"Float", 0.1, "0.1", false},
+		{"Big int", big.NewInt(-42), "-42", false},
+		{"Huge exponent", "1.5e2000000000", "1.5e2000000000", false},
+		{"Tiny exponent", "-1.5e-2000000000", "-1.5e-2000000000", false},
+		{"Exponent overflow", "1e99999999999", "", true},
+		{"Negative exponent overflow", "1e-99999999999", "", true},
+		{"Exponent past int32", "1e2147483648", "", true},
+		{"Digits past int32", "10e2147483647", "", true},
+		{"Invalid", "12.3.4", "", true},
+		{"Empty", "", "", true},
+		{"Bool", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, _ := NewDecimal(nil)
+			err := field.Process(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Process() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err == nil && field.(*Decimal).String() != tt.want {
+				t.Errorf("String() = %s, want %s", field.(*Decimal).String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimal_Search(t *testing.T) {
+	field, _ := NewDecimal(nil)
+	field.Process("19.90")
+
+	// Equal values match no matter how they are written
+	for _, search := range []any{"19.9", "019.900", "1.99e1", 19.9} {
+		searchBytes, _ := field.ToSearchBytes(search)
+		if match, _ := field.Search(searchBytes); !match {
+			t.Errorf("expected %v to match 19.90", search)
+		}
+	}
+
+	searchBytes, _ := field.ToSearchBytes("19.91")
+	if match, _ := field.Search(searchBytes); match {
+		t.Errorf("expected 19.91 to not match 19.90")
+	}
+}
+
+func TestDecimal_order(t *testing.T) {
+	values := []string{"0", "-0.5", "0.5", "-1", "1", "-10", "10", "9.99", "-9.99", "0.0001", "-0.0001", "100.01", "1e10", "-1e10"}
+	for i := 0; i < 200; i++ {
+		values = append(values, strconv.FormatFloat((rand.Float64()-0.5)*float64(rand.IntN(100000)), 'f', rand.IntN(6), 64))
+	}
+
+	// Sort by the encoded bytes and check the numbers are in order
+	type pair struct {
+		value   string
+		encoded []byte
+	}
+	pairs := make([]pair, len(values))
+	for i, value := range values {
+		encoded, err := decimalToSearchBytes(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pairs[i] = pair{value, encoded}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return bytes.Compare(pairs[i].encoded, pairs[j].encoded) < 0
+	})
+
+	for i := 1; i < len(pairs); i++ {
+		a, _ := new(big.Rat).SetString(pairs[i-1].value)
+		b, _ := new(big.Rat).SetString(pairs[i].value)
+		if a.Cmp(b) > 0 {
+			t.Errorf("expected %s to sort before %s", pairs[i].value, pairs[i-1].value)
+		}
+	}
+}
+
+func TestDecimal_orderHugeExponents(t *testing.T) {
+	values := []string{"-1e2000000000", "-1e30", "-1", "-1e-2000000000", "0", "1e-2000000000", "1", "1e30", "1e2000000000"}
+	for i := 1; i < len(values); i++ {
+		a, _ := decimalToSearchBytes(values[i-1])
+		b, _ := decimalToSearchBytes(values[i])
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("expected %s to sort before %s", values[i-1], values[i])
+		}
+	}
+}
+
+func TestDecimal_SearchRange(t *testing.T) {
+	field, _ := NewDecimal(nil)
+	field.Process("-2.5")
+
+	min, _ := field.ToSearchBytes("-3")
+	max, _ := field.ToSearchBytes("-2.49")
+	if got, _ := field.SearchRange(min, max); !got {
+		t.Errorf("expected -2.5 to be within -3 and -2.49")
+	}
+
+	min, _ = field.ToSearchBytes("-2.4")
+	max, _ = field.ToSearchBytes("10")
+	if got, _ := field.SearchRange(min, max); got {
+		t.Errorf("expected -2.5 to not be within -2.4 and 10")
+	}
+}
@@ -0,0 +1,114 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+func init() {
+	SetField("duration", NewDuration)
+}
+
+// Duration stores a time.Duration as its nanoseconds
+// with the sign bit flipped so negatives sort first
+type Duration struct {
+	v        any // original value
+	duration time.Duration
+	value    []byte
+}
+
+// NewDuration creates a new Duration that will do an exact and range search
+func NewDuration(config map[string]any) (Field, error) {
+	if err := checkConfig("duration", config); err != nil {
+		return nil, err
+	}
+
+	return &Duration{}, nil
+}
+
+// ToDuration converts a time.Duration, a string like "5m30s"
+// or an integer number of nanoseconds into a time.Duration
+func ToDuration(val any) (time.Duration, error) {
+	switch v := val.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %v", err)
+		}
+		return d, nil
+	case int:
+		return time.Duration(v), nil
+	case int8:
+		return time.Duration(v), nil
+	case int16:
+		return time.Duration(v), nil
+	case int32:
+		return time.Duration(v), nil
+	case int64:
+		return time.Duration(v), nil
+	}
+
+	return 0, fmt.Errorf("unsupported type for Duration: %T", val)
+}
+
+func durationToSearchBytes(val any) ([]byte, error) {
+	d, err := ToDuration(val)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(d)^(1<<63))
+	return b, nil
+}
+
+func (d *Duration) Type() string {
+	return DurationType
+}
+
+func (d *Duration) Value() any {
+	return d.v
+}
+
+// Duration returns the processed duration
+func (d *Duration) Duration() time.Duration {
+	return d.duration
+}
+
+// Process converts the duration to bytes and stores it in the Duration struct
+func (d *Duration) Process(val any) error {
+	// Set original value
+	d.v = val
+
+	duration, err := ToDuration(val)
+	if err != nil {
+		return fmt.Errorf("failed to process duration value: %v", err)
+	}
+	d.duration = duration
+
+	d.value, err = durationToSearchBytes(duration)
+	return err
+}
+
+func (d *Duration) ToSearchBytes(val any) ([]byte, error) {
+	return durationToSearchBytes(val)
+}
+
+// Compare returns -1, 0 or 1 comparing the durations
+func (d *Duration) Compare(other *Duration) int {
+	return bytes.Compare(d.value, other.value)
+}
+
+// Search compares the given byte slice directly with the Duration's stored byte slice
+func (d *Duration) Search(val []byte) (bool, error) {
+	return bytes.Equal(d.value, val), nil
+}
+
+// SearchRange checks if the stored value is within the given range [min, max]
+func (d *Duration) SearchRange(min, max []byte) (bool, error) {
+	return bytes.Compare(d.value, min) >= 0 && bytes.Compare(d.value, max) <= 0, nil
+}
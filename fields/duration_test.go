@@ -0,0 +1,70 @@
+package fields
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     any
+		want      time.Duration
+		expectErr bool
+	}{
+		{"Duration", 5 * time.Minute, 5 * time.Minute, false},
+		{"String", "5m30s", 5*time.Minute + 30*time.Second, false},
+		{"Nanoseconds", int64(1500), 1500, false},
+		{"Negative", "-1h", -time.Hour, false},
+		{"Invalid string", "5 minutes", 0, true},
+		{"Float", 1.5, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, _ := NewDuration(nil)
+			err := field.Process(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Process() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err == nil && field.(*Duration).Duration() != tt.want {
+				t.Errorf("Duration() = %v, want %v", field.(*Duration).Duration(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDuration_SearchRange(t *testing.T) {
+	tests := []struct {
+		value    time.Duration
+		min, max string
+		want     bool
+	}{
+		{90 * time.Second, "1m", "2m", true},
+		{90 * time.Second, "1m30s", "1m30s", true},
+		{90 * time.Second, "2m", "1h", false},
+		{-time.Minute, "-2m", "1m", true}, // Negatives sort before positives
+		{time.Minute, "-2m", "-1s", false},
+	}
+
+	for _, tt := range tests {
+		field, _ := NewDuration(nil)
+		field.Process(tt.value)
+
+		min, _ := field.ToSearchBytes(tt.min)
+		max, _ := field.ToSearchBytes(tt.max)
+		if got, _ := field.SearchRange(min, max); got != tt.want {
+			t.Errorf("SearchRange(%s, %s) on %v = %v, want %v", tt.min, tt.max, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDuration_Search(t *testing.T) {
+	field, _ := NewDuration(nil)
+	field.Process(330 * time.Second)
+
+	searchBytes, _ := field.ToSearchBytes("5m30s")
+	if match, _ := field.Search(searchBytes); !match {
+		t.Errorf("expected 5m30s to match")
+	}
+}
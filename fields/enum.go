@@ -0,0 +1,114 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	SetField("enum", NewEnum)
+}
+
+// Enum stores one of a declared set of values by its position
+// in the set, so ranges and sorting follow the declared order
+type Enum struct {
+	v      any // original value
+	values map[string]int
+	value  []byte
+}
+
+// NewEnum creates a new Enum with the "values" config
+// as a []string or a pipe separated string
+func NewEnum(config map[string]any) (Field, error) {
+	if err := checkConfig("enum", config, "values"); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	switch v := config["values"].(type) {
+	case []string:
+		values = v
+	case string:
+		values = strings.Split(v, "|")
+	default:
+		return nil, fmt.Errorf("enum requires values")
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("enum requires values")
+	}
+
+	positions := make(map[string]int, len(values))
+	for i, value := range values {
+		if _, ok := positions[value]; ok {
+			return nil, fmt.Errorf("duplicate enum value %s", value)
+		}
+		positions[value] = i
+	}
+
+	return &Enum{values: positions}, nil
+}
+
+func (e *Enum) enumToSearchBytes(val any) ([]byte, error) {
+	var str string
+	switch v := val.(type) {
+	case string:
+		str = v
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		return nil, fmt.Errorf("unsupported type for Enum: %T", val)
+	}
+
+	position, ok := e.values[str]
+	if !ok {
+		return nil, fmt.Errorf("invalid enum value %s", str)
+	}
+
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(position))
+	return b, nil
+}
+
+func (e *Enum) Type() string {
+	return EnumType
+}
+
+func (e *Enum) Value() any {
+	return e.v
+}
+
+// Process checks the value is in the set and stores its position
+func (e *Enum) Process(val any) error {
+	// Set original value
+	e.v = val
+
+	bytes, err := e.enumToSearchBytes(val)
+	if err != nil {
+		return fmt.Errorf("failed to process enum value: %v", err)
+	}
+	e.value = bytes
+	return nil
+}
+
+// ToSearchBytes returns an error for values not in the set
+func (e *Enum) ToSearchBytes(val any) ([]byte, error) {
+	return e.enumToSearchBytes(val)
+}
+
+// Compare returns -1, 0 or 1 comparing the declared order of the values
+func (e *Enum) Compare(other *Enum) int {
+	return bytes.Compare(e.value, other.value)
+}
+
+// Search compares the given byte slice directly with the Enum's stored byte slice
+func (e *Enum) Search(val []byte) (bool, error) {
+	return bytes.Equal(e.value, val), nil
+}
+
+// SearchRange checks if the stored value is within the given range [min, max]
+func (e *Enum) SearchRange(min, max []byte) (bool, error) {
+	return bytes.Compare(e.value, min) >= 0 && bytes.Compare(e.value, max) <= 0, nil
+}
@@ -0,0 +1,65 @@
+package fields
+
+import (
+	"testing"
+)
+
+func TestNewEnum(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		wantErr bool
+	}{
+		{"Slice", map[string]any{"values": []string{"open", "closed"}}, false},
+		{"Pipe separated", map[string]any{"values": "open|closed"}, false},
+		{"Missing values", nil, true},
+		{"Duplicate", map[string]any{"values": "open|open"}, true},
+		{"Wrong type", map[string]any{"values": 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEnum(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEnum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnum_validation(t *testing.T) {
+	field, _ := NewEnum(map[string]any{"values": "open|pending|closed"})
+
+	if err := field.Process("archived"); err == nil {
+		t.Errorf("expected value outside the set to error on process")
+	}
+	if _, err := field.ToSearchBytes("archived"); err == nil {
+		t.Errorf("expected value outside the set to error on search")
+	}
+
+	if err := field.Process("pending"); err != nil {
+		t.Fatal(err)
+	}
+	searchBytes, _ := field.ToSearchBytes("pending")
+	if match, _ := field.Search(searchBytes); !match {
+		t.Errorf("expected pending to match")
+	}
+}
+
+func TestEnum_order(t *testing.T) {
+	open, _ := NewEnum(map[string]any{"values": "open|pending|closed"})
+	open.Process("open")
+	closed, _ := NewEnum(map[string]any{"values": "open|pending|closed"})
+	closed.Process("closed")
+
+	// Declared order, not alphabetical
+	if open.(*Enum).Compare(closed.(*Enum)) != -1 {
+		t.Errorf("expected open to sort before closed")
+	}
+
+	min, _ := open.ToSearchBytes("open")
+	max, _ := open.ToSearchBytes("pending")
+	if got, _ := closed.SearchRange(min, max); got {
+		t.Errorf("expected closed to not be within open and pending")
+	}
+}
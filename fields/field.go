@@ -2,35 +2,60 @@ package fields
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
 )
 
 // Field types
 const (
-	TextType    = "t"
-	NumberType  = "n"
-	BooleanType = "b"
-	DateType    = "d"
+	TextType     = "t"
+	NumberType   = "n"
+	BooleanType  = "b"
+	DateType     = "d"
+	GeoType      = "g"
+	VectorType   = "v"
+	IPType       = "i"
+	KeywordType  = "k"
+	DurationType = "u"
+	EnumType     = "e"
+	DecimalType  = "m"
 )
 
 var DefaultText = "text"
 var DefaultNumber = "num"
 var DefaultBoolean = "bool"
 var DefaultDate = "date"
+var DefaultGeo = "geo"
+var DefaultVector = "vector"
+var DefaultIP = "ip"
+var DefaultKeyword = "keyword"
+var DefaultDuration = "duration"
+var DefaultEnum = "enum"
+var DefaultDecimal = "decimal"
 
 // Field is an interface that all field types must implement
-type Field struct {
-	Type  string
-	Value any
+type Field interface {
+	// Type is one of the field types, like TextType or NumberType
+	Type() string
+	Value() any
 
-	Fields map[string]FieldData
+	// Process will take in an any value and
+	// use it to fill out the struct fields
+	Process(val any) error
+
+	// To use the struct values to calculate how search
+	// bytes should be passed to the search function
+	ToSearchBytes(val any) ([]byte, error)
+
+	Search(val []byte) (bool, error)
+	SearchRange(min, max []byte) (bool, error)
 }
 
-type FieldData interface {
-	// Process(val any) error
-	// ToSearchBytes(val any) ([]byte, error)
-	// Search(val []byte) (bool, error)
-	// SearchRange(min, max []byte) (bool, error)
+// Scorer is implemented by fields that can score how relevant they
+// are to the search bytes of a match, the rest score 1 when they match
+type Scorer interface {
+	Score(val []byte) (float64, error)
 }
 
 type storage struct {
@@ -77,3 +102,20 @@ func GetField(name string, config map[string]any) (Field, error) {
 	}
 	return fieldFunc(config)
 }
+
+// checkConfig returns an error for the first config key, in order, that
+// the field does not use so a typo in an option is not silently ignored
+func checkConfig(field string, config map[string]any, keys ...string) error {
+	var unknown []string
+	for key := range config {
+		if !slices.Contains(keys, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown option %q for %s field", unknown[0], field)
+}
@@ -0,0 +1,271 @@
+package fields
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	SetField("geo", NewGeo)
+}
+
+// EarthRadius is the mean radius of the earth in meters
+const EarthRadius = 6371008.8
+
+// geohashBase32 is the alphabet used by geohashes
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeoPoint is a latitude and longitude in degrees
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Geo stores a geo point as a geohash so points in the
+// same cell share a prefix and can be matched quickly
+type Geo struct {
+	v         any // original value
+	point     GeoPoint
+	value     []byte
+	precision int
+}
+
+// NewGeo creates a new Geo with the given configuration
+func NewGeo(config map[string]any) (Field, error) {
+	if err := checkConfig("geo", config, "precision"); err != nil {
+		return nil, err
+	}
+
+	// Default precision is 12 characters, roughly 3.7cm x 1.9cm
+	precision := 12
+	if val, ok := config["precision"]; ok {
+		if prec, ok := val.(int); ok && prec >= 1 && prec <= 12 {
+			precision = prec
+		} else {
+			return nil, fmt.Errorf("invalid precision value")
+		}
+	}
+
+	return &Geo{precision: precision}, nil
+}
+
+// ToGeoPoint converts a GeoPoint, a [2]float64 or []float64 of
+// lat and lon, or a "lat,lon" string into a GeoPoint
+func ToGeoPoint(val any) (GeoPoint, error) {
+	var point GeoPoint
+
+	switch v := val.(type) {
+	case GeoPoint:
+		point = v
+	case *GeoPoint:
+		if v == nil {
+			return GeoPoint{}, fmt.Errorf("Geo requires a non nil point")
+		}
+		point = *v
+	case [2]float64:
+		point = GeoPoint{Lat: v[0], Lon: v[1]}
+	case []float64:
+		if len(v) != 2 {
+			return GeoPoint{}, fmt.Errorf("Geo requires 2 values for lat and lon, got %d", len(v))
+		}
+		point = GeoPoint{Lat: v[0], Lon: v[1]}
+	case string:
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return GeoPoint{}, fmt.Errorf("Geo requires a \"lat,lon\" string")
+		}
+
+		var err error
+		point.Lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err != nil {
+			return GeoPoint{}, fmt.Errorf("invalid latitude: %v", err)
+		}
+		point.Lon, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return GeoPoint{}, fmt.Errorf("invalid longitude: %v", err)
+		}
+	default:
+		return GeoPoint{}, fmt.Errorf("unsupported type for Geo: %T", v)
+	}
+
+	if point.Lat < -90 || point.Lat > 90 {
+		return GeoPoint{}, fmt.Errorf("latitude %v out of range", point.Lat)
+	}
+	if point.Lon < -180 || point.Lon > 180 {
+		return GeoPoint{}, fmt.Errorf("longitude %v out of range", point.Lon)
+	}
+
+	return point, nil
+}
+
+// Geohash encodes the point as a geohash of the given precision
+func Geohash(point GeoPoint, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+
+	hash := make([]byte, 0, precision)
+	bit, ch := 0, 0
+	even := true
+	for len(hash) < precision {
+		// Alternate between longitude and latitude bits
+		if even {
+			mid := (minLon + maxLon) / 2
+			if point.Lon >= mid {
+				ch |= 1 << (4 - bit)
+				minLon = mid
+			} else {
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if point.Lat >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+			continue
+		}
+
+		hash = append(hash, geohashBase32[ch])
+		bit, ch = 0, 0
+	}
+
+	return string(hash)
+}
+
+// GeoDistance returns the great circle distance
+// between two points in meters
+func GeoDistance(a, b GeoPoint) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func (g *Geo) Type() string {
+	return GeoType
+}
+
+func (g *Geo) Value() any {
+	return g.v
+}
+
+// Point returns the processed geo point
+func (g *Geo) Point() GeoPoint {
+	return g.point
+}
+
+// Geohash returns the geohash of the point at the given precision
+func (g *Geo) Geohash(precision int) string {
+	return Geohash(g.point, precision)
+}
+
+// Process converts the value to a geo point and stores its geohash
+func (g *Geo) Process(val any) error {
+	point, err := ToGeoPoint(val)
+	if err != nil {
+		return fmt.Errorf("failed to process geo value: %v", err)
+	}
+
+	// Set original value
+	g.v = val
+
+	g.point = point
+	g.value = []byte(Geohash(point, g.precision))
+	return nil
+}
+
+// ToSearchBytes converts a point to its geohash. A string that is
+// not a "lat,lon" pair is used as a geohash cell to search within
+func (g *Geo) ToSearchBytes(val any) ([]byte, error) {
+	if str, ok := val.(string); ok && !strings.Contains(str, ",") {
+		str = strings.ToLower(str)
+		for _, c := range str {
+			if !strings.ContainsRune(geohashBase32, c) {
+				return nil, fmt.Errorf("invalid geohash %q", str)
+			}
+		}
+		return []byte(str), nil
+	}
+
+	point, err := ToGeoPoint(val)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(Geohash(point, g.precision)), nil
+}
+
+// Search checks if the point is inside the geohash cell,
+// a full precision geohash is an exact point match
+func (g *Geo) Search(val []byte) (bool, error) {
+	if len(val) == 0 {
+		return false, fmt.Errorf("invalid search value for Geo")
+	}
+	return bytes.HasPrefix(g.value, val), nil
+}
+
+// SearchRange for Geo is not applicable but implemented to satisfy the interface
+func (g *Geo) SearchRange(min, max []byte) (bool, error) {
+	return false, fmt.Errorf("range search not supported for Geo, use a bounding box")
+}
+
+// DistanceTo returns the distance from the point in meters
+func (g *Geo) DistanceTo(point GeoPoint) float64 {
+	return GeoDistance(g.point, point)
+}
+
+// InDistance checks if the point is within distance meters of the given point
+func (g *Geo) InDistance(point GeoPoint, distance float64) bool {
+	// Quick reject on latitude before doing the trig,
+	// a degree of latitude is always about the same distance
+	if math.Abs(g.point.Lat-point.Lat)*EarthRadius*math.Pi/180 > distance {
+		return false
+	}
+	return g.DistanceTo(point) <= distance
+}
+
+// InBoundingBox checks if the point is inside the box. If the top left
+// longitude is greater than the bottom right the box crosses the dateline
+func (g *Geo) InBoundingBox(topLeft, bottomRight GeoPoint) bool {
+	if g.point.Lat > topLeft.Lat || g.point.Lat < bottomRight.Lat {
+		return false
+	}
+
+	if topLeft.Lon <= bottomRight.Lon {
+		return g.point.Lon >= topLeft.Lon && g.point.Lon <= bottomRight.Lon
+	}
+	return g.point.Lon >= topLeft.Lon || g.point.Lon <= bottomRight.Lon
+}
+
+// InPolygon checks if the point is inside the polygon using ray casting
+func (g *Geo) InPolygon(points []GeoPoint) bool {
+	if len(points) < 3 {
+		return false
+	}
+
+	inside := false
+	j := len(points) - 1
+	for i := range points {
+		a, b := points[i], points[j]
+		if (a.Lat > g.point.Lat) != (b.Lat > g.point.Lat) &&
+			g.point.Lon < (b.Lon-a.Lon)*(g.point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
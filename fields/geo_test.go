@@ -0,0 +1,142 @@
+package fields
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeo_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     any
+		expectErr bool
+	}{
+		{"GeoPoint", GeoPoint{Lat: 40.7128, Lon: -74.0060}, false},
+		{"Array", [2]float64{40.7128, -74.0060}, false},
+		{"Slice", []float64{40.7128, -74.0060}, false},
+		{"String", "40.7128,-74.0060", false},
+		{"Slice wrong length", []float64{40.7128}, true},
+		{"Latitude out of range", GeoPoint{Lat: 91, Lon: 0}, true},
+		{"Longitude out of range", GeoPoint{Lat: 0, Lon: 181}, true},
+		{"Int", 42, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, _ := NewGeo(nil)
+			err := field.Process(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Process() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		point     GeoPoint
+		precision int
+		want      string
+	}{
+		{GeoPoint{Lat: 57.64911, Lon: 10.40744}, 11, "u4pruydqqvj"},
+		{GeoPoint{Lat: 42.6, Lon: -5.6}, 5, "ezs42"},
+		{GeoPoint{Lat: 0, Lon: 0}, 1, "s"},
+	}
+
+	for _, tt := range tests {
+		if got := Geohash(tt.point, tt.precision); got != tt.want {
+			t.Errorf("Geohash(%v, %d) = %s, want %s", tt.point, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestGeoDistance(t *testing.T) {
+	newYork := GeoPoint{Lat: 40.7128, Lon: -74.0060}
+	london := GeoPoint{Lat: 51.5074, Lon: -0.1278}
+
+	// New York to London is about 5570km
+	distance := GeoDistance(newYork, london)
+	if math.Abs(distance-5570000) > 10000 {
+		t.Errorf("GeoDistance() = %v, want about 5570000", distance)
+	}
+
+	if GeoDistance(newYork, newYork) != 0 {
+		t.Errorf("GeoDistance() to itself should be 0")
+	}
+}
+
+func TestGeo_Search(t *testing.T) {
+	field, _ := NewGeo(nil)
+	field.Process(GeoPoint{Lat: 57.64911, Lon: 10.40744})
+
+	tests := []struct {
+		name  string
+		value any
+		match bool
+	}{
+		{"Exact point", GeoPoint{Lat: 57.64911, Lon: 10.40744}, true},
+		{"Containing cell", "u4pru", true},
+		{"Other cell", "ezs42", false},
+		{"Other point", "42.6,-5.6", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchBytes, err := field.ToSearchBytes(tt.value)
+			if err != nil {
+				t.Fatalf("ToSearchBytes() error = %v", err)
+			}
+
+			match, err := field.Search(searchBytes)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if match != tt.match {
+				t.Errorf("Search() = %v, want %v", match, tt.match)
+			}
+		})
+	}
+
+	if _, err := field.ToSearchBytes("not a geohash!"); err == nil {
+		t.Errorf("ToSearchBytes() expected error for invalid geohash")
+	}
+}
+
+func TestGeo_Shapes(t *testing.T) {
+	field, _ := NewGeo(nil)
+	field.Process(GeoPoint{Lat: 40.7128, Lon: -74.0060}) // New York
+	geo := field.(*Geo)
+
+	// Distance
+	if !geo.InDistance(GeoPoint{Lat: 40.7306, Lon: -73.9352}, 10000) {
+		t.Errorf("InDistance() expected Brooklyn within 10km")
+	}
+	if geo.InDistance(GeoPoint{Lat: 51.5074, Lon: -0.1278}, 10000) {
+		t.Errorf("InDistance() expected London not within 10km")
+	}
+
+	// Bounding box
+	if !geo.InBoundingBox(GeoPoint{Lat: 41, Lon: -75}, GeoPoint{Lat: 40, Lon: -73}) {
+		t.Errorf("InBoundingBox() expected point in box")
+	}
+	if geo.InBoundingBox(GeoPoint{Lat: 42, Lon: -73}, GeoPoint{Lat: 41, Lon: -72}) {
+		t.Errorf("InBoundingBox() expected point outside box")
+	}
+
+	// Bounding box crossing the dateline
+	fiji, _ := NewGeo(nil)
+	fiji.Process(GeoPoint{Lat: -17.7, Lon: 178.0})
+	if !fiji.(*Geo).InBoundingBox(GeoPoint{Lat: -10, Lon: 170}, GeoPoint{Lat: -20, Lon: -170}) {
+		t.Errorf("InBoundingBox() expected point in box crossing the dateline")
+	}
+
+	// Polygon
+	square := []GeoPoint{{Lat: 41, Lon: -75}, {Lat: 41, Lon: -73}, {Lat: 40, Lon: -73}, {Lat: 40, Lon: -75}}
+	if !geo.InPolygon(square) {
+		t.Errorf("InPolygon() expected point in polygon")
+	}
+	triangle := []GeoPoint{{Lat: 41, Lon: -73}, {Lat: 41, Lon: -72}, {Lat: 40, Lon: -72}}
+	if geo.InPolygon(triangle) {
+		t.Errorf("InPolygon() expected point outside polygon")
+	}
+}
@@ -0,0 +1,185 @@
+package fields
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+func init() {
+	SetField("ip", NewIP)
+}
+
+// IP stores an IPv4 or IPv6 address as 16 bytes so addresses
+// compare in numeric order. IPv4 addresses are stored IPv4 mapped
+type IP struct {
+	v     any // original value
+	addr  netip.Addr
+	value []byte
+}
+
+// NewIP creates a new IP that will do an exact, cidr and range search
+func NewIP(config map[string]any) (Field, error) {
+	if err := checkConfig("ip", config); err != nil {
+		return nil, err
+	}
+
+	return &IP{}, nil
+}
+
+// ToAddr converts a netip.Addr, net.IP or string into a netip.Addr
+func ToAddr(val any) (netip.Addr, error) {
+	var addr netip.Addr
+
+	switch v := val.(type) {
+	case netip.Addr:
+		addr = v
+	case *netip.Addr:
+		if v == nil {
+			return netip.Addr{}, fmt.Errorf("IP requires a non nil address")
+		}
+		addr = *v
+	case net.IP:
+		var ok bool
+		addr, ok = netip.AddrFromSlice(v)
+		if !ok {
+			return netip.Addr{}, fmt.Errorf("invalid IP %v", v)
+		}
+	case string:
+		var err error
+		addr, err = netip.ParseAddr(v)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("invalid IP: %v", err)
+		}
+	default:
+		return netip.Addr{}, fmt.Errorf("unsupported type for IP: %T", v)
+	}
+
+	if !addr.IsValid() {
+		return netip.Addr{}, fmt.Errorf("invalid IP %v", val)
+	}
+
+	// net.IP stores IPv4 addresses as IPv4 mapped IPv6
+	return addr.Unmap(), nil
+}
+
+// ToPrefix converts a netip.Prefix, *net.IPNet or "10.0.0.0/8" string into a netip.Prefix
+func ToPrefix(val any) (netip.Prefix, error) {
+	var prefix netip.Prefix
+
+	switch v := val.(type) {
+	case netip.Prefix:
+		prefix = v
+	case *net.IPNet:
+		if v == nil {
+			return netip.Prefix{}, fmt.Errorf("cidr requires a non nil network")
+		}
+		addr, ok := netip.AddrFromSlice(v.IP)
+		if !ok {
+			return netip.Prefix{}, fmt.Errorf("invalid cidr %v", v)
+		}
+		bits, _ := v.Mask.Size()
+		prefix = netip.PrefixFrom(addr.Unmap(), bits)
+	case string:
+		var err error
+		prefix, err = netip.ParsePrefix(v)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid cidr: %v", err)
+		}
+	default:
+		return netip.Prefix{}, fmt.Errorf("unsupported type for cidr: %T", v)
+	}
+
+	if !prefix.IsValid() {
+		return netip.Prefix{}, fmt.Errorf("invalid cidr %v", val)
+	}
+
+	return prefix.Masked(), nil
+}
+
+// CIDRToSearchRange returns the search bytes for the first and
+// last address in the cidr to be used with SearchRange
+func CIDRToSearchRange(val any) ([]byte, []byte, error) {
+	prefix, err := ToPrefix(val)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	first := prefix.Addr().As16()
+	last := first
+
+	// Set every bit after the prefix for the last address
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		bits += 96
+	}
+	for i := bits; i < 128; i++ {
+		last[i/8] |= 1 << (7 - i%8)
+	}
+
+	return first[:], last[:], nil
+}
+
+func ipToSearchBytes(val any) ([]byte, error) {
+	addr, err := ToAddr(val)
+	if err != nil {
+		return nil, err
+	}
+
+	b := addr.As16()
+	return b[:], nil
+}
+
+func (ip *IP) Type() string {
+	return IPType
+}
+
+func (ip *IP) Value() any {
+	return ip.v
+}
+
+// Addr returns the processed address
+func (ip *IP) Addr() netip.Addr {
+	return ip.addr
+}
+
+// Process converts the address to bytes and stores it in the IP struct
+func (ip *IP) Process(val any) error {
+	// Set original value
+	ip.v = val
+
+	addr, err := ToAddr(val)
+	if err != nil {
+		return fmt.Errorf("failed to process IP value: %v", err)
+	}
+	ip.addr = addr
+
+	b := addr.As16()
+	ip.value = b[:]
+	return nil
+}
+
+func (ip *IP) ToSearchBytes(val any) ([]byte, error) {
+	return ipToSearchBytes(val)
+}
+
+// InCIDR checks if the address is within the cidr
+func (ip *IP) InCIDR(prefix netip.Prefix) bool {
+	return prefix.Masked().Contains(ip.addr)
+}
+
+// Compare returns -1, 0 or 1 comparing the address in numeric order
+func (ip *IP) Compare(other *IP) int {
+	return bytes.Compare(ip.value, other.value)
+}
+
+// Search compares the given byte slice directly with the IP's stored byte slice
+func (ip *IP) Search(val []byte) (bool, error) {
+	return bytes.Equal(ip.value, val), nil
+}
+
+// SearchRange checks if the stored address is within the given range [min, max]
+func (ip *IP) SearchRange(min, max []byte) (bool, error) {
+	return bytes.Compare(ip.value, min) >= 0 && bytes.Compare(ip.value, max) <= 0, nil
+}
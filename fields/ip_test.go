@@ -0,0 +1,135 @@
+package fields
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestIP_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     any
+		want      string
+		expectErr bool
+	}{
+		{"Addr", netip.MustParseAddr("10.1.2.3"), "10.1.2.3", false},
+		{"NetIP", net.ParseIP("10.1.2.3"), "10.1.2.3", false},
+		{"NetIP4", net.IPv4(10, 1, 2, 3).To4(), "10.1.2.3", false},
+		{"String", "192.168.0.1", "192.168.0.1", false},
+		{"IPv6", "2001:db8::1", "2001:db8::1", false},
+		{"Invalid string", "10.1.2", "", true},
+		{"Zero addr", netip.Addr{}, "", true},
+		{"Int", 42, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, _ := NewIP(nil)
+			err := field.Process(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Process() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if err == nil && field.(*IP).Addr().String() != tt.want {
+				t.Errorf("Process() addr = %s, want %s", field.(*IP).Addr(), tt.want)
+			}
+		})
+	}
+}
+
+func TestIP_Search(t *testing.T) {
+	field, _ := NewIP(nil)
+	field.Process(net.ParseIP("10.0.0.1"))
+
+	// IPv4 from a string and a net.IP match the same
+	searchBytes, _ := field.ToSearchBytes("10.0.0.1")
+	match, err := field.Search(searchBytes)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !match {
+		t.Errorf("Expected search to match, but it did not")
+	}
+
+	nonMatchBytes, _ := field.ToSearchBytes("10.0.0.2")
+	nonMatch, _ := field.Search(nonMatchBytes)
+	if nonMatch {
+		t.Errorf("Expected search with non-matching ip to not match, but it did")
+	}
+}
+
+func TestIP_CIDR(t *testing.T) {
+	tests := []struct {
+		ip   string
+		cidr string
+		want bool
+	}{
+		{"10.1.2.3", "10.0.0.0/8", true},
+		{"11.0.0.0", "10.0.0.0/8", false},
+		{"10.255.255.255", "10.0.0.0/8", true},
+		{"192.168.1.10", "192.168.1.0/24", true},
+		{"192.168.2.10", "192.168.1.0/24", false},
+		{"192.168.1.10", "192.168.1.77/24", true}, // Host bits are masked
+		{"2001:db8::1", "2001:db8::/32", true},
+		{"2001:db9::1", "2001:db8::/32", false},
+		{"10.1.2.3", "2001:db8::/32", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip+" in "+tt.cidr, func(t *testing.T) {
+			field, _ := NewIP(nil)
+			field.Process(tt.ip)
+
+			prefix, err := ToPrefix(tt.cidr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := field.(*IP).InCIDR(prefix); got != tt.want {
+				t.Errorf("InCIDR() = %v, want %v", got, tt.want)
+			}
+
+			// The cidr as a range should agree
+			min, max, err := CIDRToSearchRange(tt.cidr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := field.SearchRange(min, max); got != tt.want {
+				t.Errorf("SearchRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIP_SearchRange(t *testing.T) {
+	field, _ := NewIP(nil)
+	field.Process("10.0.0.200")
+
+	tests := []struct {
+		min, max string
+		want     bool
+	}{
+		{"10.0.0.1", "10.0.0.255", true},
+		{"10.0.0.200", "10.0.0.200", true},
+		{"10.0.0.9", "10.0.0.100", false}, // Numeric not string order
+		{"10.0.0.201", "10.0.1.0", false},
+	}
+
+	for _, tt := range tests {
+		min, _ := field.ToSearchBytes(tt.min)
+		max, _ := field.ToSearchBytes(tt.max)
+		if got, _ := field.SearchRange(min, max); got != tt.want {
+			t.Errorf("SearchRange(%s, %s) = %v, want %v", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestIP_Compare(t *testing.T) {
+	a, _ := NewIP(nil)
+	a.Process("10.0.0.9")
+	b, _ := NewIP(nil)
+	b.Process("10.0.0.10")
+
+	if a.(*IP).Compare(b.(*IP)) != -1 {
+		t.Errorf("expected 10.0.0.9 to sort before 10.0.0.10")
+	}
+}
@@ -0,0 +1,91 @@
+package fields
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	SetField("keyword", NewKeyword)
+}
+
+// Keyword stores the whole string as is for
+// exact matches, sorting and facets
+type Keyword struct {
+	v         any // original value
+	value     []byte
+	lowercase bool
+}
+
+// NewKeyword creates a new Keyword, set lowercase
+// in the config to match without case
+func NewKeyword(config map[string]any) (Field, error) {
+	if err := checkConfig("keyword", config, "lowercase"); err != nil {
+		return nil, err
+	}
+
+	lowercase := false
+	if val, ok := config["lowercase"]; ok {
+		if lower, ok := val.(bool); ok {
+			lowercase = lower
+		} else {
+			return nil, fmt.Errorf("invalid lowercase value")
+		}
+	}
+
+	return &Keyword{lowercase: lowercase}, nil
+}
+
+func (k *Keyword) keywordToSearchBytes(val any) ([]byte, error) {
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type for Keyword: %T", val)
+	}
+
+	if k.lowercase {
+		str = strings.ToLower(str)
+	}
+
+	return []byte(str), nil
+}
+
+func (k *Keyword) Type() string {
+	return KeywordType
+}
+
+func (k *Keyword) Value() any {
+	return k.v
+}
+
+// Process stores the string as bytes
+func (k *Keyword) Process(val any) error {
+	// Set original value
+	k.v = val
+
+	bytes, err := k.keywordToSearchBytes(val)
+	if err != nil {
+		return fmt.Errorf("failed to process keyword value: %v", err)
+	}
+	k.value = bytes
+	return nil
+}
+
+func (k *Keyword) ToSearchBytes(val any) ([]byte, error) {
+	return k.keywordToSearchBytes(val)
+}
+
+// Compare returns -1, 0 or 1 comparing the keywords byte by byte
+func (k *Keyword) Compare(other *Keyword) int {
+	return bytes.Compare(k.value, other.value)
+}
+
+// Search compares the given byte slice directly with the Keyword's stored byte slice
+func (k *Keyword) Search(val []byte) (bool, error) {
+	return bytes.Equal(k.value, val), nil
+}
+
+// SearchRange checks if the stored value is within the given range [min, max]
+func (k *Keyword) SearchRange(min, max []byte) (bool, error) {
+	return bytes.Compare(k.value, min) >= 0 && bytes.Compare(k.value, max) <= 0, nil
+}
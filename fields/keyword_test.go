@@ -0,0 +1,61 @@
+package fields
+
+import (
+	"testing"
+)
+
+func TestKeyword_Search(t *testing.T) {
+	tests := []struct {
+		name      string
+		lowercase bool
+		value     string
+		search    string
+		want      bool
+	}{
+		{"Exact", false, "Billy Smith", "Billy Smith", true},
+		{"Partial", false, "Billy Smith", "Billy", false},
+		{"Case", false, "Billy Smith", "billy smith", false},
+		{"Lowercase", true, "Billy Smith", "billy SMITH", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, _ := NewKeyword(map[string]any{"lowercase": tt.lowercase})
+			field.Process(tt.value)
+
+			searchBytes, _ := field.ToSearchBytes(tt.search)
+			if got, _ := field.Search(searchBytes); got != tt.want {
+				t.Errorf("Search(%q) on %q = %v, want %v", tt.search, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyword_SearchRange(t *testing.T) {
+	field, _ := NewKeyword(nil)
+	field.Process("m")
+
+	min, _ := field.ToSearchBytes("a")
+	max, _ := field.ToSearchBytes("n")
+	if got, _ := field.SearchRange(min, max); !got {
+		t.Errorf("expected m to be within a and n")
+	}
+}
+
+func TestKeyword_Compare(t *testing.T) {
+	a, _ := NewKeyword(nil)
+	a.Process("apple")
+	b, _ := NewKeyword(nil)
+	b.Process("banana")
+
+	if a.(*Keyword).Compare(b.(*Keyword)) != -1 {
+		t.Errorf("expected apple to sort before banana")
+	}
+}
+
+func TestKeyword_Process(t *testing.T) {
+	field, _ := NewKeyword(nil)
+	if err := field.Process(42); err == nil {
+		t.Errorf("expected non string to error")
+	}
+}
@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 )
 
 func init() {
 	SetField("num", NewNum)
 }
 
-// Num stores the numeric value directly as bytes
+// numKeySize is the width of every encoded number regardless of its kind
+const numKeySize = 16
+
+// Num stores the numeric value as a sortable key so
+// equal numbers of any kind have the same bytes
 type Num struct {
 	v     any // original value
 	value []byte
@@ -18,32 +23,93 @@ type Num struct {
 
 // NewNum creates a new Num that will do an exact and range search
 func NewNum(config map[string]any) (Field, error) {
+	if err := checkConfig("num", config); err != nil {
+		return nil, err
+	}
+
 	return &Num{}, nil
 }
 
-// NumToSearchBytes converts a numeric value to a byte slice
+// numToSearchBytes converts a numeric value to a 16 byte key that sorts
+// byte by byte in numeric order. The first 8 bytes are the value as a
+// transformed float64 and the last 8 bytes are the sign flipped
+// difference between an int and that float, so large ints that round
+// to the same float still keep their exact order
 func numToSearchBytes(value any) ([]byte, error) {
-	var err error
-	buf := new(bytes.Buffer)
+	var f float64
+	var rem int64
 
 	switch v := value.(type) {
 	case int:
-		err = binary.Write(buf, binary.BigEndian, int64(v))
-	case int8, int16, int32, int64, float32, float64:
-		err = binary.Write(buf, binary.BigEndian, v)
+		f, rem = intKey(int64(v))
+	case int8:
+		f, rem = intKey(int64(v))
+	case int16:
+		f, rem = intKey(int64(v))
+	case int32:
+		f, rem = intKey(int64(v))
+	case int64:
+		f, rem = intKey(v)
 	case uint:
-		err = binary.Write(buf, binary.BigEndian, uint64(v))
-	case uint8, uint16, uint32, uint64:
-		err = binary.Write(buf, binary.BigEndian, v)
+		f, rem = uintKey(uint64(v))
+	case uint8:
+		f, rem = uintKey(uint64(v))
+	case uint16:
+		f, rem = uintKey(uint64(v))
+	case uint32:
+		f, rem = uintKey(uint64(v))
+	case uint64:
+		f, rem = uintKey(v)
+	case float32:
+		f = float64(v)
+	case float64:
+		f = v
 	default:
 		return nil, fmt.Errorf("unsupported type for Num: %T", v)
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("error converting numeric value to bytes: %v", err)
+	key := make([]byte, numKeySize)
+	binary.BigEndian.PutUint64(key, floatKey(f))
+	binary.BigEndian.PutUint64(key[8:], uint64(rem)^(1<<63))
+	return key, nil
+}
+
+// floatKey flips the sign bit of positive floats and every bit of
+// negative floats so the unsigned value sorts in numeric order.
+// -0 is the same as 0 and NaN sorts after +Inf
+func floatKey(f float64) uint64 {
+	switch {
+	case f == 0:
+		f = 0
+	case math.IsNaN(f):
+		f = math.NaN()
 	}
 
-	return buf.Bytes(), nil
+	bits := math.Float64bits(f)
+	if bits&(1<<63) != 0 {
+		return ^bits
+	}
+	return bits | (1 << 63)
+}
+
+// intKey splits an int into the nearest float64 and the remainder
+func intKey(i int64) (float64, int64) {
+	f := float64(i)
+	if f >= math.MaxInt64 {
+		// Rounded up to 2^63 which doesnt fit in an int64
+		return f, int64(uint64(i) - (1 << 63))
+	}
+	return f, i - int64(f)
+}
+
+// uintKey splits a uint into the nearest float64 and the remainder
+func uintKey(u uint64) (float64, int64) {
+	f := float64(u)
+	if f >= math.MaxUint64 {
+		// Rounded up to 2^64 which doesnt fit in a uint64
+		return f, -int64(^u + 1)
+	}
+	return f, int64(u - uint64(f))
 }
 
 func (n *Num) Type() string {
@@ -55,7 +121,7 @@ func (n *Num) Value() any {
 }
 
 // Process converts a numeric value to bytes
-// and stores it in the Num struct using NumToSearchBytes
+// and stores it in the Num struct using numToSearchBytes
 func (n *Num) Process(val any) error {
 	// Set original value
 	n.v = val
@@ -72,6 +138,11 @@ func (n *Num) ToSearchBytes(val any) ([]byte, error) {
 	return numToSearchBytes(val)
 }
 
+// Compare returns -1, 0 or 1 comparing the numbers in numeric order
+func (n *Num) Compare(other *Num) int {
+	return bytes.Compare(n.value, other.value)
+}
+
 // Search compares the given byte slice directly with the Num's stored byte slice
 func (n *Num) Search(val []byte) (bool, error) {
 	return bytes.Equal(n.value, val), nil
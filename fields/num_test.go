@@ -1,6 +1,10 @@
 package fields
 
-import "testing"
+import (
+	"bytes"
+	"math"
+	"testing"
+)
 
 func TestNum_Process(t *testing.T) {
 	tests := []struct {
@@ -107,3 +111,119 @@ func TestNum_SearchRange(t *testing.T) {
 		})
 	}
 }
+
+func TestNum_CrossType(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+	}{
+		{"Int8 and int64", int8(42), int64(42)},
+		{"Int and uint", 42, uint(42)},
+		{"Int and float", -7, float64(-7)},
+		{"Uint8 and float32", uint8(3), float32(3)},
+		{"Zero and negative zero", 0, math.Copysign(0, -1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nf := &Num{}
+			if err := nf.Process(tt.a); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			searchVal, err := numToSearchBytes(tt.b)
+			if err != nil {
+				t.Fatalf("numToSearchBytes() error = %v", err)
+			}
+
+			if len(searchVal) != numKeySize {
+				t.Errorf("numToSearchBytes() len = %d, want %d", len(searchVal), numKeySize)
+			}
+
+			gotMatch, _ := nf.Search(searchVal)
+			if !gotMatch {
+				t.Errorf("Search() %v does not match %v", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestNum_Order(t *testing.T) {
+	// Values in ascending numeric order
+	values := []any{
+		math.Inf(-1),
+		int64(math.MinInt64),
+		-1e18,
+		int64(-1<<53 - 1),
+		-100,
+		int8(-1),
+		-0.5,
+		0,
+		float32(0.25),
+		uint8(1),
+		1.5,
+		int16(100),
+		uint32(math.MaxUint32),
+		int64(1 << 53),
+		int64(1<<53 + 1),
+		int64(math.MaxInt64 - 1),
+		int64(math.MaxInt64),
+		uint64(math.MaxInt64 + 1),
+		uint64(math.MaxUint64 - 1),
+		uint64(math.MaxUint64),
+		1e20,
+		math.Inf(1),
+		math.NaN(),
+	}
+
+	keys := make([][]byte, len(values))
+	for i, v := range values {
+		key, err := numToSearchBytes(v)
+		if err != nil {
+			t.Fatalf("numToSearchBytes(%v) error = %v", v, err)
+		}
+		keys[i] = key
+	}
+
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) >= 0 {
+			t.Errorf("numToSearchBytes(%v) should sort before numToSearchBytes(%v)", values[i-1], values[i])
+		}
+	}
+}
+
+func TestNum_SearchRangeNegative(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         any
+		min, max      any
+		expectInRange bool
+	}{
+		{"Negative in range", -5, -10, 10, true},
+		{"Negative below range", -50, -10, 10, false},
+		{"Positive above negative range", 1, -10, -1, false},
+		{"Negative float in range", -2.5, -3, int8(-2), true},
+		{"Negative float below range", -3.5, -3, int8(-2), false},
+		{"Uint in float range", uint64(7), 6.5, 7.5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nf := &Num{}
+			if err := nf.Process(tt.value); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			min, _ := nf.ToSearchBytes(tt.min)
+			max, _ := nf.ToSearchBytes(tt.max)
+			inRange, err := nf.SearchRange(min, max)
+			if err != nil {
+				t.Fatalf("SearchRange() error = %v", err)
+			}
+
+			if inRange != tt.expectInRange {
+				t.Errorf("SearchRange() inRange = %v, expectInRange %v", inRange, tt.expectInRange)
+			}
+		})
+	}
+}
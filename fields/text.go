@@ -0,0 +1,219 @@
+package fields
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brianvoe/gofindit/tokenizers"
+	"github.com/brianvoe/gofindit/tokenizers/charfilters"
+)
+
+func init() {
+	SetField("text", NewText)
+}
+
+// tokenSeparator joins tokens into search bytes
+const tokenSeparator = "\x1f"
+
+// Text runs the value through an analyzer
+// so it can be searched by its tokens
+type Text struct {
+	v         any // original value
+	analyzer  string
+	tokenizer tokenizers.Tokenizer
+	values    []tokenizers.Tokenizer // one per non blank value
+	offsets   []*TextOffsets         // one per value, nil if not char filtered
+}
+
+// TextOffsets is a value after the char filters of its analyzer
+// and the offsets that map positions in it back to the value
+type TextOffsets struct {
+	Filtered string
+	Offsets  charfilters.Offsets
+}
+
+// NewText creates a new Text with the given configuration
+//   - analyzer: registered tokenizer, words by default
+//   - min, max and edge: n-gram lengths and anchoring for ngram analyzers
+func NewText(config map[string]any) (Field, error) {
+	if err := checkConfig("text", config, "analyzer", "min", "max", "edge"); err != nil {
+		return nil, err
+	}
+
+	// Default analyzer is "words"
+	analyzer := "words"
+	if val, ok := config["analyzer"]; ok {
+		if name, ok := val.(string); ok && name != "" {
+			analyzer = name
+		} else {
+			return nil, fmt.Errorf("invalid analyzer value")
+		}
+	}
+
+	tokenizer, err := tokenizers.NewTokenizer(analyzer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid analyzer: %v", err)
+	}
+
+	// N-gram analyzers can change their lengths and edge
+	_, hasMin := config["min"]
+	_, hasMax := config["max"]
+	_, hasEdge := config["edge"]
+	if hasMin || hasMax || hasEdge {
+		ngram, ok := tokenizer.(*tokenizers.NGram)
+		if !ok {
+			return nil, fmt.Errorf("min, max and edge are only for ngram analyzers, not %s", analyzer)
+		}
+
+		options := ngram.Options()
+		if hasMin {
+			if options.Min, ok = config["min"].(int); !ok || options.Min < 1 {
+				return nil, fmt.Errorf("invalid min value")
+			}
+		}
+		if hasMax {
+			if options.Max, ok = config["max"].(int); !ok || options.Max < 1 {
+				return nil, fmt.Errorf("invalid max value")
+			}
+		}
+		if hasEdge {
+			if options.Edge, ok = config["edge"].(bool); !ok {
+				return nil, fmt.Errorf("invalid edge value")
+			}
+		}
+		tokenizer = tokenizers.NewNGramOptions(options)
+	}
+
+	return &Text{analyzer: analyzer, tokenizer: tokenizer}, nil
+}
+
+func (t *Text) Type() string {
+	return TextType
+}
+
+func (t *Text) Value() any {
+	return t.v
+}
+
+// Analyzer returns the name of the tokenizer used
+func (t *Text) Analyzer() string {
+	return t.analyzer
+}
+
+// Process runs the string, or each string in a slice, through the
+// analyzer. Blank strings are stored without any tokens
+func (t *Text) Process(val any) error {
+	var strs []string
+	switch v := val.(type) {
+	case string:
+		strs = []string{v}
+	case []string:
+		strs = v
+	default:
+		return fmt.Errorf("unsupported type for Text: %T", val)
+	}
+
+	// Set original value
+	t.v = val
+
+	// Each value gets its own tokenizer so a
+	// phrase cannot match across two values
+	t.values = nil
+	t.offsets = make([]*TextOffsets, len(strs))
+	for n, str := range strs {
+		if strings.TrimSpace(str) == "" {
+			continue
+		}
+
+		// Copies keep the options the analyzer was configured with
+		tokenizer := t.tokenizer
+		if len(t.values) > 0 {
+			copier, ok := t.tokenizer.(tokenizers.Copier)
+			if !ok {
+				return fmt.Errorf("failed to process text value: analyzer %s cannot be copied", t.analyzer)
+			}
+			tokenizer = copier.Copy()
+		}
+
+		err := tokenizer.Process(str)
+		if err != nil {
+			return fmt.Errorf("failed to process text value: %v", err)
+		}
+		t.values = append(t.values, tokenizer)
+
+		if offsetter, ok := tokenizer.(tokenizers.Offsetter); ok {
+			t.offsets[n] = &TextOffsets{Filtered: offsetter.Filtered(), Offsets: offsetter.Offsets()}
+		}
+	}
+	return nil
+}
+
+// Offsets returns the filtered text and offsets of each value, in the
+// order of the values. A value is nil if it was blank or the analyzer
+// has no char filters, its positions are already in the value
+func (t *Text) Offsets() []*TextOffsets {
+	return t.offsets
+}
+
+// ToSearchBytes runs the value through the analyzer and joins the tokens
+func (t *Text) ToSearchBytes(val any) ([]byte, error) {
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type for Text: %T", val)
+	}
+
+	tokens, err := t.tokenizer.ToSearch(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.Join(tokens, tokenSeparator)), nil
+}
+
+// Search checks the analyzer of each value for the tokens from ToSearchBytes
+func (t *Text) Search(val []byte) (bool, error) {
+	if len(val) == 0 {
+		return false, nil
+	}
+
+	tokens := strings.Split(string(val), tokenSeparator)
+	for _, tokenizer := range t.values {
+		matched, err := tokenizer.Search(tokens)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+// Score returns the best score of the values for the tokens
+// from ToSearchBytes. Analyzers that cannot score the tokens
+// score 1 for a value they match
+func (t *Text) Score(val []byte) (float64, error) {
+	if len(val) == 0 {
+		return 0, nil
+	}
+
+	tokens := strings.Split(string(val), tokenSeparator)
+	best := 0.0
+	for _, tokenizer := range t.values {
+		if scorer, ok := tokenizer.(tokenizers.Scorer); ok {
+			best = max(best, scorer.Score(tokens))
+			continue
+		}
+
+		matched, err := tokenizer.Search(tokens)
+		if err != nil {
+			return 0, err
+		}
+		if matched {
+			best = max(best, 1)
+		}
+	}
+	return best, nil
+}
+
+// SearchRange is not supported on text, use a keyword field
+func (t *Text) SearchRange(min, max []byte) (bool, error) {
+	return false, fmt.Errorf("range search not supported on text")
+}
@@ -0,0 +1,143 @@
+package fields
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText_Search(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer string
+		value    string
+		search   string
+		want     bool
+	}{
+		{"Words match", "", "Billy is my friend", "my friend", true},
+		{"Words case and accents", "", "Crème Brûlée", "creme brulee", true},
+		{"Words out of order", "", "Billy is my friend", "friend my", false},
+		{"Words no match", "", "Billy is my friend", "enemy", false},
+		{"Edge ngram prefix", "edge_ngram", "Billy Smith", "smi", true},
+		{"Edge ngram middle", "edge_ngram", "Billy Smith", "mit", false},
+		{"Ngram middle", "ngram", "Billy Smith", "mit", true},
+		{"Blank value", "", "  ", "billy", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]any{}
+			if tt.analyzer != "" {
+				config["analyzer"] = tt.analyzer
+			}
+
+			field, err := NewText(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := field.Process(tt.value); err != nil {
+				t.Fatal(err)
+			}
+
+			searchBytes, err := field.ToSearchBytes(tt.search)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := field.Search(searchBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Search(%q) on %q = %v, want %v", tt.search, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText_slice(t *testing.T) {
+	field, _ := NewText(nil)
+	if err := field.Process([]string{"rock climbing", "chess"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{"chess", true},
+		{"rock climbing", true},
+		{"climbing chess", false}, // Phrases do not cross values
+		{"golf", false},
+	}
+
+	for _, tt := range tests {
+		searchBytes, _ := field.ToSearchBytes(tt.search)
+		if got, _ := field.Search(searchBytes); got != tt.want {
+			t.Errorf("Search(%q) = %v, want %v", tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestText_offsets(t *testing.T) {
+	field, _ := NewText(map[string]any{"analyzer": "html"})
+	values := []string{"<b>Tom</b> &amp; <i>Jerry</i>", " ", "<p>Spike</p> and <em>Tyke</em>"}
+	if err := field.Process(values); err != nil {
+		t.Fatal(err)
+	}
+
+	offsets := field.(*Text).Offsets()
+	if len(offsets) != len(values) || offsets[1] != nil {
+		t.Fatalf("expected offsets for each non blank value, got %v", offsets)
+	}
+
+	// Positions in the filtered text map back into their own value
+	tests := []struct {
+		value int
+		word  string
+	}{
+		{0, "Tom"},
+		{0, "Jerry"},
+		{2, "Spike"},
+		{2, "Tyke"},
+	}
+	for _, tt := range tests {
+		value := offsets[tt.value]
+		start := strings.Index(value.Filtered, tt.word)
+		if start < 0 {
+			t.Fatalf("expected %q in filtered value %q", tt.word, value.Filtered)
+		}
+
+		original := value.Offsets.Original(start)
+		if got := values[tt.value][original : original+len(tt.word)]; got != tt.word {
+			t.Errorf("expected offset of %q to point at it in value %d, got %q", tt.word, tt.value, got)
+		}
+	}
+
+	// Analyzers without char filters have no offsets
+	words, _ := NewText(nil)
+	words.Process("Tom & Jerry")
+	if offsets := words.(*Text).Offsets(); len(offsets) != 1 || offsets[0] != nil {
+		t.Errorf("expected no offsets without char filters, got %v", offsets)
+	}
+}
+
+func TestText_separateState(t *testing.T) {
+	// Fields with the same analyzer do not share processed values
+	a, _ := NewText(map[string]any{"analyzer": "ngram"})
+	b, _ := NewText(map[string]any{"analyzer": "ngram"})
+	a.Process("apple")
+	b.Process("banana")
+
+	searchBytes, _ := a.ToSearchBytes("nan")
+	if match, _ := a.Search(searchBytes); match {
+		t.Errorf("expected apple to not match nan")
+	}
+}
+
+func TestNewText_invalidAnalyzer(t *testing.T) {
+	if _, err := NewText(map[string]any{"analyzer": "nope"}); err == nil {
+		t.Errorf("expected unknown analyzer to error")
+	}
+	if _, err := NewText(map[string]any{"analyzer": 1}); err == nil {
+		t.Errorf("expected non string analyzer to error")
+	}
+}
@@ -0,0 +1,227 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+func init() {
+	SetField("vector", NewVector)
+}
+
+// Vector similarity functions
+const (
+	SimilarityCosine = "cosine"
+	SimilarityDot    = "dot"
+	SimilarityL2     = "l2"
+)
+
+// Vector stores a dense vector of float32 values
+// for nearest neighbor search
+type Vector struct {
+	v          any // original value
+	vector     []float32
+	value      []byte
+	dims       int
+	similarity string
+}
+
+// NewVector creates a new Vector with the given configuration.
+// "dims" fixes the number of dimensions and "similarity" is
+// one of cosine (default), dot or l2
+func NewVector(config map[string]any) (Field, error) {
+	if err := checkConfig("vector", config, "dims", "similarity"); err != nil {
+		return nil, err
+	}
+
+	vector := &Vector{similarity: SimilarityCosine}
+
+	if val, ok := config["dims"]; ok {
+		if dims, ok := val.(int); ok && dims > 0 {
+			vector.dims = dims
+		} else {
+			return nil, fmt.Errorf("invalid dims value")
+		}
+	}
+
+	if val, ok := config["similarity"]; ok {
+		if sim, ok := val.(string); ok && isValidSimilarity(sim) {
+			vector.similarity = sim
+		} else {
+			return nil, fmt.Errorf("invalid similarity value")
+		}
+	}
+
+	return vector, nil
+}
+
+// ToVector converts a []float32 or []float64 into a []float32
+func ToVector(val any) ([]float32, error) {
+	switch v := val.(type) {
+	case []float32:
+		return v, nil
+	case []float64:
+		out := make([]float32, len(v))
+		for i, f := range v {
+			out[i] = float32(f)
+		}
+		return out, nil
+	case []any:
+		// Values decoded from json
+		out := make([]float32, len(v))
+		for i, f := range v {
+			num, ok := f.(float64)
+			if !ok {
+				return nil, fmt.Errorf("unsupported vector value type: %T", f)
+			}
+			out[i] = float32(num)
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type for Vector: %T", val)
+}
+
+// vectorToSearchBytes converts a vector to big endian float32 bytes
+func vectorToSearchBytes(vector []float32) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, vector); err != nil {
+		return nil, fmt.Errorf("error converting vector value to bytes: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (v *Vector) Type() string {
+	return VectorType
+}
+
+func (v *Vector) Value() any {
+	return v.v
+}
+
+// Vector returns the processed vector
+func (v *Vector) Vector() []float32 {
+	return v.vector
+}
+
+// Dims returns the number of dimensions in the vector
+func (v *Vector) Dims() int {
+	return len(v.vector)
+}
+
+// SimilarityName returns the similarity function the vector uses
+func (v *Vector) SimilarityName() string {
+	return v.similarity
+}
+
+// Process validates the vector dimensions and stores a copy of it
+func (v *Vector) Process(val any) error {
+	vector, err := ToVector(val)
+	if err != nil {
+		return fmt.Errorf("failed to process vector value: %v", err)
+	}
+
+	if len(vector) == 0 {
+		return fmt.Errorf("failed to process vector value: empty vector")
+	}
+	if v.dims > 0 && len(vector) != v.dims {
+		return fmt.Errorf("vector has %d dims, expected %d", len(vector), v.dims)
+	}
+
+	// Set original value
+	v.v = val
+
+	v.vector = append([]float32(nil), vector...)
+	v.value, err = vectorToSearchBytes(v.vector)
+	return err
+}
+
+func (v *Vector) ToSearchBytes(val any) ([]byte, error) {
+	vector, err := ToVector(val)
+	if err != nil {
+		return nil, err
+	}
+	return vectorToSearchBytes(vector)
+}
+
+// Search checks if the vector is exactly the same
+func (v *Vector) Search(val []byte) (bool, error) {
+	return bytes.Equal(v.value, val), nil
+}
+
+// SearchRange for Vector is not applicable but implemented to satisfy the interface
+func (v *Vector) SearchRange(min, max []byte) (bool, error) {
+	return false, fmt.Errorf("range search not supported for Vector, use a knn search")
+}
+
+// Similarity scores how similar the query is to the vector, higher is more similar
+func (v *Vector) Similarity(query []float32) (float64, error) {
+	return Similarity(v.similarity, v.vector, query)
+}
+
+// Similarity scores two vectors with the named similarity function.
+// Scores are always higher for more similar vectors
+func Similarity(name string, a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("vector has %d dims, expected %d", len(b), len(a))
+	}
+
+	switch name {
+	case SimilarityCosine, "":
+		return CosineSimilarity(a, b), nil
+	case SimilarityDot:
+		return DotProduct(a, b), nil
+	case SimilarityL2:
+		// Turn distance into a score between 0 and 1
+		distance := L2Distance(a, b)
+		return 1 / (1 + distance*distance), nil
+	}
+
+	return 0, fmt.Errorf("invalid similarity %s", name)
+}
+
+// DotProduct returns the dot product of two vectors of the same length
+func DotProduct(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// CosineSimilarity returns the cosine of the angle between
+// two vectors of the same length, from -1 to 1
+func CosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// L2Distance returns the euclidean distance between two vectors of the same length
+func L2Distance(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		diff := float64(a[i]) - float64(b[i])
+		sum += diff * diff
+	}
+	return math.Sqrt(sum)
+}
+
+// isValidSimilarity checks if the provided similarity string is valid
+func isValidSimilarity(similarity string) bool {
+	switch similarity {
+	case SimilarityCosine, SimilarityDot, SimilarityL2:
+		return true
+	}
+	return false
+}
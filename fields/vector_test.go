@@ -0,0 +1,100 @@
+package fields
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVector_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    map[string]any
+		input     any
+		expectErr bool
+	}{
+		{"Float32", nil, []float32{1, 2, 3}, false},
+		{"Float64", nil, []float64{1, 2, 3}, false},
+		{"Json", nil, []any{1.0, 2.0, 3.0}, false},
+		{"Dims match", map[string]any{"dims": 3}, []float32{1, 2, 3}, false},
+		{"Dims mismatch", map[string]any{"dims": 2}, []float32{1, 2, 3}, true},
+		{"Empty", nil, []float32{}, true},
+		{"String", nil, "not a vector", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := NewVector(tt.config)
+			if err != nil {
+				t.Fatalf("NewVector() error = %v", err)
+			}
+
+			err = field.Process(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Process() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+
+	// Invalid config
+	if _, err := NewVector(map[string]any{"similarity": "manhattan"}); err == nil {
+		t.Errorf("NewVector() expected error for invalid similarity")
+	}
+	if _, err := NewVector(map[string]any{"dims": -1}); err == nil {
+		t.Errorf("NewVector() expected error for invalid dims")
+	}
+}
+
+func TestVector_Search(t *testing.T) {
+	field, _ := NewVector(nil)
+	field.Process([]float32{0.5, 0.25})
+
+	searchBytes, _ := field.ToSearchBytes([]float64{0.5, 0.25})
+	match, err := field.Search(searchBytes)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !match {
+		t.Errorf("Expected search to match, but it did not")
+	}
+
+	searchBytes, _ = field.ToSearchBytes([]float32{0.5, 0.5})
+	match, _ = field.Search(searchBytes)
+	if match {
+		t.Errorf("Expected search to not match, but it did")
+	}
+}
+
+func TestVector_Similarity(t *testing.T) {
+	tests := []struct {
+		similarity string
+		a          []float32
+		b          []float32
+		want       float64
+	}{
+		{SimilarityCosine, []float32{1, 0}, []float32{1, 0}, 1},
+		{SimilarityCosine, []float32{1, 0}, []float32{0, 1}, 0},
+		{SimilarityCosine, []float32{1, 0}, []float32{-1, 0}, -1},
+		{SimilarityCosine, []float32{1, 0}, []float32{2, 0}, 1},
+		{SimilarityDot, []float32{1, 2}, []float32{3, 4}, 11},
+		{SimilarityL2, []float32{0, 0}, []float32{0, 0}, 1},
+		{SimilarityL2, []float32{0, 0}, []float32{3, 4}, 1.0 / 26},
+	}
+
+	for _, tt := range tests {
+		field, _ := NewVector(map[string]any{"similarity": tt.similarity})
+		field.Process(tt.a)
+
+		got, err := field.(*Vector).Similarity(tt.b)
+		if err != nil {
+			t.Fatalf("Similarity() error = %v", err)
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%s, %v, %v) = %v, want %v", tt.similarity, tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// Mismatched dims
+	if _, err := Similarity(SimilarityCosine, []float32{1}, []float32{1, 2}); err == nil {
+		t.Errorf("Similarity() expected error for mismatched dims")
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/brianvoe/gofindit
 
-go 1.22.0
+go 1.23.0
 
 require golang.org/x/text v0.14.0
@@ -48,11 +48,21 @@ func stringToAny(value string) (any, error) {
 		}
 
 		// Loop through the type flags and if they are not all the same, then it's an array of any
+		// unless they are all numbers, then ints are widened to floats
 		shouldBeAny := false
+		isNumbers := true
 		for _, flag := range typeFlags {
 			if flag != typeFlags[0] {
 				shouldBeAny = true
-				break
+			}
+			if flag != "int" && flag != "float" {
+				isNumbers = false
+			}
+		}
+		if shouldBeAny && isNumbers {
+			shouldBeAny = false
+			for i := range typeFlags {
+				typeFlags[i] = "float"
 			}
 		}
 
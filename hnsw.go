@@ -0,0 +1,318 @@
+package gofindit
+
+import (
+	"container/heap"
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+// HNSWOptions configures the approximate nearest neighbor graph
+// built for vector fields
+type HNSWOptions struct {
+	M              int // Max neighbors per node per layer, layer 0 gets twice as many
+	EfConstruction int // Candidates considered when inserting a node
+	EfSearch       int // Default candidates considered when searching
+}
+
+// sanatize sets default values for any options not set
+func (ho HNSWOptions) sanatize() HNSWOptions {
+	if ho.M <= 0 {
+		ho.M = 16
+	}
+	if ho.EfConstruction <= 0 {
+		ho.EfConstruction = 200
+	}
+	if ho.EfSearch <= 0 {
+		ho.EfSearch = 100
+	}
+	return ho
+}
+
+// hnswNode is a single vector in the graph
+type hnswNode struct {
+	id        string
+	vector    []float32
+	neighbors [][]int // Neighbors per layer
+	deleted   bool
+}
+
+// hnsw is a hierarchical navigable small world graph
+// used for approximate nearest neighbor search
+type hnsw struct {
+	options    HNSWOptions
+	similarity string
+	levelMult  float64
+
+	nodes    []*hnswNode
+	ids      map[string]int
+	deleted  int // Nodes marked deleted that are still in the graph
+	entry    int
+	maxLevel int
+
+	rand *rand.Rand
+}
+
+func newHNSW(options HNSWOptions, similarity string) *hnsw {
+	options = options.sanatize()
+
+	return &hnsw{
+		options:    options,
+		similarity: similarity,
+		levelMult:  1 / math.Log(float64(options.M)),
+		ids:        make(map[string]int),
+		entry:      -1,
+		rand:       rand.New(rand.NewPCG(1, 2)),
+	}
+}
+
+// distance turns similarity into a distance where lower is closer
+func (h *hnsw) distance(a, b []float32) float64 {
+	score, err := fields.Similarity(h.similarity, a, b)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return -score
+}
+
+// maxNeighbors returns the max number of neighbors for a layer
+func (h *hnsw) maxNeighbors(level int) int {
+	if level == 0 {
+		return h.options.M * 2
+	}
+	return h.options.M
+}
+
+// add inserts a vector into the graph, replacing it if the id already exists
+func (h *hnsw) add(id string, vector []float32) {
+	h.remove(id)
+
+	level := int(math.Floor(-math.Log(1-h.rand.Float64()) * h.levelMult))
+	node := &hnswNode{
+		id:        id,
+		vector:    vector,
+		neighbors: make([][]int, level+1),
+	}
+	index := len(h.nodes)
+	h.nodes = append(h.nodes, node)
+	h.ids[id] = index
+
+	// First node becomes the entry point
+	if h.entry < 0 {
+		h.entry = index
+		h.maxLevel = level
+		return
+	}
+
+	// Greedy search down to the level the node is inserted at
+	entry := h.entry
+	for l := h.maxLevel; l > level; l-- {
+		entry = h.searchLayer(vector, []int{entry}, 1, l)[0].index
+	}
+
+	entries := []int{entry}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLayer(vector, entries, h.options.EfConstruction, l)
+
+		// Connect to the closest candidates
+		neighbors := candidates
+		if len(neighbors) > h.options.M {
+			neighbors = neighbors[:h.options.M]
+		}
+		for _, neighbor := range neighbors {
+			node.neighbors[l] = append(node.neighbors[l], neighbor.index)
+			h.connect(neighbor.index, index, l)
+		}
+
+		entries = entries[:0]
+		for _, candidate := range candidates {
+			entries = append(entries, candidate.index)
+		}
+	}
+
+	if level > h.maxLevel {
+		h.entry = index
+		h.maxLevel = level
+	}
+}
+
+// connect adds a link from node to neighbor keeping
+// only the closest links if there are too many
+func (h *hnsw) connect(node, neighbor, level int) {
+	n := h.nodes[node]
+	n.neighbors[level] = append(n.neighbors[level], neighbor)
+
+	max := h.maxNeighbors(level)
+	if len(n.neighbors[level]) <= max {
+		return
+	}
+
+	sort.Slice(n.neighbors[level], func(i, j int) bool {
+		return h.distance(n.vector, h.nodes[n.neighbors[level][i]].vector) <
+			h.distance(n.vector, h.nodes[n.neighbors[level][j]].vector)
+	})
+	n.neighbors[level] = n.neighbors[level][:max]
+}
+
+// hnswCompactRatio is the share of the nodes that can be
+// deleted before the graph is rebuilt without them
+const hnswCompactRatio = 0.5
+
+// remove marks the id as deleted, it stays in the graph so other
+// nodes can still be reached through it until the graph is compacted
+func (h *hnsw) remove(id string) {
+	index, ok := h.ids[id]
+	if !ok {
+		return
+	}
+	h.nodes[index].deleted = true
+	delete(h.ids, id)
+	h.deleted++
+
+	if float64(h.deleted) > float64(len(h.nodes))*hnswCompactRatio {
+		h.compact()
+	}
+}
+
+// compact rebuilds the graph from the nodes that are not deleted.
+// It costs as much as adding them again, which is spread out over
+// the deletes it takes to get back past the compact ratio
+func (h *hnsw) compact() {
+	nodes := h.nodes
+
+	h.nodes = nil
+	h.ids = make(map[string]int, len(nodes)-h.deleted)
+	h.deleted = 0
+	h.entry = -1
+	h.maxLevel = 0
+
+	for _, node := range nodes {
+		if !node.deleted {
+			h.add(node.id, node.vector)
+		}
+	}
+}
+
+// search returns the ids of the k closest vectors to the query that
+// pass the allow check, considering at least ef candidates. allow can be nil
+func (h *hnsw) search(query []float32, k int, ef int, allow func(id string) bool) []string {
+	if h.entry < 0 || k <= 0 {
+		return nil
+	}
+	if ef < k {
+		ef = k
+	}
+
+	entry := h.entry
+	for l := h.maxLevel; l > 0; l-- {
+		entry = h.searchLayer(query, []int{entry}, 1, l)[0].index
+	}
+
+	// Deleted and filtered out nodes still take up room in the
+	// candidates, so keep widening the search until k are allowed
+	for {
+		var ids []string
+		candidates := h.searchLayer(query, []int{entry}, ef, 0)
+		for _, candidate := range candidates {
+			node := h.nodes[candidate.index]
+			if node.deleted || (allow != nil && !allow(node.id)) {
+				continue
+			}
+
+			ids = append(ids, node.id)
+			if len(ids) == k {
+				break
+			}
+		}
+
+		// Fewer candidates than ef means every reachable node was checked
+		if len(ids) == k || len(candidates) < ef || ef >= len(h.nodes)-h.deleted {
+			return ids
+		}
+		ef *= 2
+	}
+}
+
+// searchLayer returns up to ef of the closest nodes to the
+// query on a single layer sorted from closest to furthest
+func (h *hnsw) searchLayer(query []float32, entries []int, ef int, level int) []hnswItem {
+	visited := make(map[int]bool, ef*2)
+	candidates := &hnswQueue{}           // Closest first
+	results := &hnswQueue{reverse: true} // Furthest first
+
+	for _, entry := range entries {
+		item := hnswItem{index: entry, distance: h.distance(query, h.nodes[entry].vector)}
+		visited[entry] = true
+		heap.Push(candidates, item)
+		heap.Push(results, item)
+	}
+
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(hnswItem)
+
+		// Stop once the closest candidate is further than the furthest result
+		if results.Len() >= ef && current.distance > results.items[0].distance {
+			break
+		}
+
+		node := h.nodes[current.index]
+		if level >= len(node.neighbors) {
+			continue
+		}
+
+		for _, neighbor := range node.neighbors[level] {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+
+			item := hnswItem{index: neighbor, distance: h.distance(query, h.nodes[neighbor].vector)}
+			if results.Len() < ef || item.distance < results.items[0].distance {
+				heap.Push(candidates, item)
+				heap.Push(results, item)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	sorted := make([]hnswItem, results.Len())
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(results).(hnswItem)
+	}
+	return sorted
+}
+
+// hnswItem is a node and its distance from the query
+type hnswItem struct {
+	index    int
+	distance float64
+}
+
+// hnswQueue is a heap of items, closest first unless reversed
+type hnswQueue struct {
+	items   []hnswItem
+	reverse bool
+}
+
+func (q hnswQueue) Len() int { return len(q.items) }
+
+func (q hnswQueue) Less(i, j int) bool {
+	if q.reverse {
+		return q.items[i].distance > q.items[j].distance
+	}
+	return q.items[i].distance < q.items[j].distance
+}
+
+func (q hnswQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *hnswQueue) Push(x any) { q.items = append(q.items, x.(hnswItem)) }
+
+func (q *hnswQueue) Pop() any {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
@@ -0,0 +1,186 @@
+package gofindit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Fusion methods for combining hybrid sub query results
+const (
+	FusionRRF    = "rrf"    // Reciprocal rank fusion
+	FusionLinear = "linear" // Weighted sum of min-max normalized scores
+)
+
+// HybridQuery runs multiple sub queries, like a text query and
+// a knn query, and fuses their ranked results into a single list
+type HybridQuery struct {
+	Limit   uint             `json:"limit"`
+	Skip    uint             `json:"skip"`
+	Queries []HybridSubQuery `json:"queries"`
+	Fusion  string           `json:"fusion"` // rrf or linear
+
+	// RankConstant is the k in 1 / (k + rank) for rrf, defaults to 60
+	RankConstant int `json:"rank_constant"`
+}
+
+// HybridSubQuery is a single query to fuse. The Query limit is how
+// many ranked results from it are considered in the fusion
+type HybridSubQuery struct {
+	Name   string      `json:"name"` // Key in the hit scores, defaults to the query position
+	Query  SearchQuery `json:"query"`
+	Weight float64     `json:"weight"` // Only used by linear fusion, defaults to 1
+}
+
+func (hq *HybridQuery) Sanatize() {
+	// If limit is 0, set it to 10
+	if hq.Limit == 0 {
+		hq.Limit = 10
+	}
+
+	if hq.Fusion == "" {
+		hq.Fusion = FusionRRF
+	}
+
+	if hq.RankConstant == 0 {
+		hq.RankConstant = 60
+	}
+
+	for i := range hq.Queries {
+		if hq.Queries[i].Name == "" {
+			hq.Queries[i].Name = strconv.Itoa(i)
+		}
+		if hq.Queries[i].Weight == 0 {
+			hq.Queries[i].Weight = 1
+		}
+
+		// Consider more than the final limit from each query so
+		// documents ranked lower in one can still make it in
+		if hq.Queries[i].Query.Limit == 0 {
+			hq.Queries[i].Query.Limit = (hq.Limit + hq.Skip) * 5
+		}
+	}
+}
+
+func (hq *HybridQuery) Validate() error {
+	if len(hq.Queries) == 0 {
+		return fmt.Errorf("hybrid query requires at least one query")
+	}
+
+	if hq.Fusion != FusionRRF && hq.Fusion != FusionLinear {
+		return fmt.Errorf("invalid fusion type %s", hq.Fusion)
+	}
+
+	if hq.RankConstant < 0 {
+		return fmt.Errorf("rank_constant cannot be negative")
+	}
+
+	names := make(map[string]bool, len(hq.Queries))
+	for _, query := range hq.Queries {
+		if names[query.Name] {
+			return fmt.Errorf("duplicate hybrid query name %s", query.Name)
+		}
+		names[query.Name] = true
+
+		if query.Weight < 0 {
+			return fmt.Errorf("hybrid query %s weight cannot be negative", query.Name)
+		}
+	}
+
+	return nil
+}
+
+// Hybrid runs each sub query against the index and fuses
+// the results into a single ranked search response
+func (i *Index) Hybrid(hybridQuery HybridQuery) (*SearchResponse, error) {
+	return i.HybridContext(context.Background(), hybridQuery)
+}
+
+// HybridContext runs the hybrid query, the sub queries stop
+// with the context error if the context is done. The response
+// is timed out if any sub query reached its timeout
+func (i *Index) HybridContext(ctx context.Context, hybridQuery HybridQuery) (*SearchResponse, error) {
+	// Set default values if none set
+	hybridQuery.Sanatize()
+
+	// Validate the hybrid query
+	err := hybridQuery.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	fused := make(map[*Document]*Hit)
+	timedOut := false
+	for _, sub := range hybridQuery.Queries {
+		result, err := i.search(ctx, sub.Query)
+		if err != nil {
+			return nil, fmt.Errorf("hybrid query %s: %w", sub.Name, err)
+		}
+		hits := result.hits
+		timedOut = timedOut || result.timedOut
+
+		// Find the score range for normalizing
+		minScore, maxScore := math.Inf(1), math.Inf(-1)
+		for _, h := range hits {
+			minScore = math.Min(minScore, h.score)
+			maxScore = math.Max(maxScore, h.score)
+		}
+
+		for rank, h := range hits {
+			result, ok := fused[h.doc]
+			if !ok {
+				hit := newHit(h.doc, 0)
+				hit.Scores = make(map[string]float64)
+				result = &hit
+				fused[h.doc] = result
+			}
+			result.Scores[sub.Name] = h.score
+
+			switch hybridQuery.Fusion {
+			case FusionRRF:
+				result.Score += 1 / float64(hybridQuery.RankConstant+rank+1)
+			case FusionLinear:
+				normalized := 1.0
+				if maxScore > minScore {
+					normalized = (h.score - minScore) / (maxScore - minScore)
+				}
+				result.Score += sub.Weight * normalized
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(fused))
+	for _, result := range fused {
+		hits = append(hits, *result)
+	}
+
+	// Sort by fused score with id as a tie breaker so results are stable
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	response := &SearchResponse{Total: len(hits), TimedOut: timedOut}
+
+	// Handle skip
+	if int(hybridQuery.Skip) > len(hits) {
+		hits = hits[:0]
+	} else {
+		hits = hits[hybridQuery.Skip:]
+	}
+
+	// Handle limit
+	if int(hybridQuery.Limit) < len(hits) {
+		hits = hits[:hybridQuery.Limit]
+	}
+
+	response.Hits = hits
+	return response, nil
+}
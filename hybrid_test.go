@@ -0,0 +1,130 @@
+package gofindit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndex_Hybrid(t *testing.T) {
+	index := newTestEmbeddingIndex(t, Options{})
+
+	// Every odd document is in the lexical results
+	lexical := SearchQuery{
+		Limit:  100,
+		Fields: []SearchQueryField{{Field: "category", Type: "match", Value: "odd"}},
+	}
+	semantic := SearchQuery{
+		KNN: &KNNQuery{Field: "embedding", Vector: []float32{10, 90, 1}, K: 5},
+	}
+
+	for _, fusion := range []string{FusionRRF, FusionLinear} {
+		t.Run(fusion, func(t *testing.T) {
+			response, err := index.Hybrid(HybridQuery{
+				Limit:  3,
+				Fusion: fusion,
+				Queries: []HybridSubQuery{
+					{Name: "text", Query: lexical},
+					{Name: "knn", Query: semantic, Weight: 2},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(response.Hits) != 3 {
+				t.Fatalf("expected 3 hits, got %d", len(response.Hits))
+			}
+
+			if response.Total <= len(response.Hits) {
+				t.Errorf("expected total to include hits past the limit, got %d", response.Total)
+			}
+
+			// Hits are sorted by score
+			for i := 1; i < len(response.Hits); i++ {
+				if response.Hits[i].Score > response.Hits[i-1].Score {
+					t.Errorf("expected hits sorted by score, got %v then %v", response.Hits[i-1].Score, response.Hits[i].Score)
+				}
+			}
+
+			// Documents found by both queries rank first and have both scores
+			top := response.Hits[0]
+			if _, ok := top.Scores["text"]; !ok {
+				t.Errorf("expected top hit to have a text score, got %v", top.Scores)
+			}
+			if _, ok := top.Scores["knn"]; !ok {
+				t.Errorf("expected top hit to have a knn score, got %v", top.Scores)
+			}
+			if top.Document.(TestEmbedding).Category != "odd" {
+				t.Errorf("expected top hit to be odd, got %s", top.Document.(TestEmbedding).Name)
+			}
+		})
+	}
+}
+
+func TestIndex_Hybrid_lexicalRank(t *testing.T) {
+	// Ids are in the reverse order of how relevant the names are
+	index := New()
+	docs := map[string]string{
+		"a": "red shoes for running in the rain",
+		"b": "red red shoes",
+		"c": "red",
+	}
+	for id, name := range docs {
+		if err := index.Index(id, TestEmbedding{Name: name, Category: "shoes", Embedding: []float32{1, 1, 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, fusion := range []string{FusionRRF, FusionLinear} {
+		t.Run(fusion, func(t *testing.T) {
+			response, err := index.Hybrid(HybridQuery{
+				Fusion: fusion,
+				Queries: []HybridSubQuery{
+					{Name: "text", Query: SearchQuery{Fields: []SearchQueryField{{Field: "name", Type: "match", Value: "red"}}}},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, hit := range response.Hits {
+				ids = append(ids, hit.ID)
+			}
+			if !reflect.DeepEqual(ids, []string{"c", "b", "a"}) {
+				t.Errorf("expected hits ranked by relevance, got %v", ids)
+			}
+
+			// Shorter names and repeats score higher
+			for i := 1; i < len(response.Hits); i++ {
+				if response.Hits[i].Scores["text"] >= response.Hits[i-1].Scores["text"] {
+					t.Errorf("expected text scores to go down, got %v then %v", response.Hits[i-1].Scores["text"], response.Hits[i].Scores["text"])
+				}
+			}
+		})
+	}
+}
+
+func TestHybridQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   HybridQuery
+		wantErr bool
+	}{
+		{"no queries", HybridQuery{}, true},
+		{"invalid fusion", HybridQuery{Fusion: "max", Queries: []HybridSubQuery{{}}}, true},
+		{"duplicate names", HybridQuery{Queries: []HybridSubQuery{{Name: "a"}, {Name: "a"}}}, true},
+		{"negative weight", HybridQuery{Queries: []HybridSubQuery{{Weight: -1}}}, true},
+		{"valid", HybridQuery{Queries: []HybridSubQuery{{}, {}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Sanatize()
+			err := tt.query.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
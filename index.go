@@ -1,20 +1,56 @@
 package gofindit
 
 import (
+	"context"
 	"errors"
 	"math/rand/v2"
+	"slices"
 	"sync"
+	"time"
+
+	"github.com/brianvoe/gofindit/fields"
+	"github.com/brianvoe/gofindit/tokenizers/filters"
 )
 
+// FilterFunc is a token filter from the filters package
+type FilterFunc = filters.Func
+
+// DefaultFilters are the filters used by New
+var DefaultFilters = []FilterFunc{filters.Lowercase}
+
 type Index struct {
 	Documents map[string]*Document
 
+	// geoCells are the geohash prefixes of each geo field
+	geoCells map[string]geoCells
+
 	Filters []FilterFunc
 
 	// Cache
 	Cache     bool
 	CacheSize int
 
+	// HNSW builds approximate nearest neighbor graphs for vector fields
+	HNSW *HNSWOptions
+
+	vectors   map[string]*vectorField
+	docValues map[string]*docValues
+	nextNum   int   // Internal number of the next document
+	seqNo     int64 // Seq no of the last write
+
+	// TombstoneTTL is how long the version of a document deleted with an
+	// external version is kept, so an older write that comes in after
+	// the delete cannot bring it back. Older writes after it expires are
+	// taken as new documents, the trade-off for not keeping every id
+	TombstoneTTL time.Duration
+
+	tombstones     map[string]tombstone
+	tombstoneOrder []tombstoneExpiry // Oldest first, for expiring them
+
+	pits    map[string]*pointInTime
+	pitMu   sync.Mutex
+	removed []*Document // Removed while a point in time was open, doc values not freed yet
+
 	mu sync.RWMutex
 }
 
@@ -25,14 +61,30 @@ type Options struct {
 
 	// Filters
 	Filters []FilterFunc // The filters to apply to strings
+
+	// Vectors
+	HNSW *HNSWOptions // Approximate knn search for vector fields, nil for exact only
+
+	// Versions
+	TombstoneTTL time.Duration // How long deletes with an external version are kept, defaults to DefaultTombstoneTTL
 }
 
+// DefaultTombstoneTTL is how long deletes with an external version are kept
+const DefaultTombstoneTTL = time.Minute
+
 func New() *Index {
 	index := Index{
 		Documents: make(map[string]*Document),
+		geoCells:  make(map[string]geoCells),
 		Filters:   DefaultFilters,
 		Cache:     true,
 		CacheSize: 100,
+		vectors:   make(map[string]*vectorField),
+		docValues: make(map[string]*docValues),
+		pits:      make(map[string]*pointInTime),
+
+		TombstoneTTL: DefaultTombstoneTTL,
+		tombstones:   make(map[string]tombstone),
 	}
 
 	return &index
@@ -42,9 +94,20 @@ func New() *Index {
 func NewOptions(options Options) *Index {
 	index := Index{
 		Documents: make(map[string]*Document),
+		geoCells:  make(map[string]geoCells),
 		Filters:   options.Filters,
 		Cache:     options.Cache,
 		CacheSize: options.CacheSize,
+		HNSW:      options.HNSW,
+		vectors:   make(map[string]*vectorField),
+		docValues: make(map[string]*docValues),
+		pits:      make(map[string]*pointInTime),
+
+		TombstoneTTL: options.TombstoneTTL,
+		tombstones:   make(map[string]tombstone),
+	}
+	if index.TombstoneTTL == 0 {
+		index.TombstoneTTL = DefaultTombstoneTTL
 	}
 
 	return &index
@@ -63,6 +126,16 @@ func (i *Index) Random() (string, any) {
 }
 
 func (i *Index) Index(id string, doc any) error {
+	return i.IndexContext(context.Background(), id, doc)
+}
+
+// IndexContext indexes the document unless the context is done
+// before it is added, then the context error is returned
+func (i *Index) IndexContext(ctx context.Context, id string, doc any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
@@ -75,12 +148,103 @@ func (i *Index) Index(id string, doc any) error {
 	if err != nil {
 		return err
 	}
+	docNew.ID = id
+
+	// Waiting for the lock could take a while
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	i.setVersion(docNew, i.tombstoneVersion(id), 0)
+	return i.addDocument(docNew)
+}
+
+// addDocument adds an analyzed document to the index under its id.
+// The lock must be held and the id must not be taken
+func (i *Index) addDocument(doc *Document) error {
+	err := i.indexVectors(doc.ID, doc)
+	if err != nil {
+		return err
+	}
+
+	doc.num = i.nextNum
+	i.nextNum++
+	i.indexDocValues(doc)
+
+	i.Documents[doc.ID] = doc
+	i.indexGeo(doc.ID, doc)
+	delete(i.tombstones, doc.ID)
+
+	return nil
+}
+
+// restoreDocument puts a removed document back under its own number so
+// the doc values of a point in time still line up. The lock must be held
+func (i *Index) restoreDocument(doc *Document) error {
+	err := i.indexVectors(doc.ID, doc)
+	if err != nil {
+		return err
+	}
+
+	// The doc values may not have been freed yet
+	i.removed = slices.DeleteFunc(i.removed, func(removed *Document) bool { return removed == doc })
+	i.removeDocValues(doc)
+	i.indexDocValues(doc)
 
-	i.Documents[id] = docNew
+	i.Documents[doc.ID] = doc
+	i.indexGeo(doc.ID, doc)
 
 	return nil
 }
 
+// replaceDocument swaps the document for a new one with the same id.
+// The old document is left in place if the new one cannot be added
+func (i *Index) replaceDocument(old *Document, doc *Document) error {
+	if err := i.checkVectors(doc); err != nil {
+		return err
+	}
+
+	i.removeDocument(old)
+	return i.addDocument(doc)
+}
+
+// removeDocument removes the document from the index. Documents are
+// never changed, so the doc values are kept while a point in time is
+// open in case it still has the document
+func (i *Index) removeDocument(doc *Document) {
+	delete(i.Documents, doc.ID)
+
+	for name, field := range doc.Fields {
+		if _, ok := field.(*fields.Vector); !ok {
+			continue
+		}
+		if vf, ok := i.vectors[name]; ok && vf.graph != nil {
+			vf.graph.remove(doc.ID)
+		}
+	}
+	i.removeGeo(doc.ID, doc)
+
+	i.removed = append(i.removed, doc)
+	i.freeRemoved()
+}
+
+// freeRemoved frees the doc values of removed documents once
+// no point in time is open. The lock must be held
+func (i *Index) freeRemoved() {
+	i.pitMu.Lock()
+	i.expirePITs()
+	pitsOpen := len(i.pits) > 0
+	i.pitMu.Unlock()
+	if pitsOpen {
+		return
+	}
+
+	for _, doc := range i.removed {
+		i.removeDocValues(doc)
+	}
+	i.removed = nil
+}
+
 // Get returns the document with the given ID
 func (i *Index) Get(id string) (any, error) {
 	i.mu.RLock()
@@ -93,3 +257,29 @@ func (i *Index) Get(id string) (any, error) {
 
 	return doc.Original, nil
 }
+
+// GetHit returns the document with the given ID with its version and seq no
+func (i *Index) GetHit(id string) (Hit, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	doc, ok := i.Documents[id]
+	if !ok {
+		return Hit{}, errors.New("document not found")
+	}
+
+	return newHit(doc, 0), nil
+}
+
+// GetVersion returns the version and seq no of the document with the given ID
+func (i *Index) GetVersion(id string) (int64, int64, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	doc, ok := i.Documents[id]
+	if !ok {
+		return 0, 0, errors.New("document not found")
+	}
+
+	return doc.version, doc.seqNo, nil
+}
@@ -1,7 +1,9 @@
 package gofindit
 
 import (
+	"context"
 	"fmt"
+	"testing"
 )
 
 func Example() {
@@ -38,3 +40,23 @@ func Example() {
 
 	// Output: {Name:Test Age:10}
 }
+
+func TestIndex_IndexContext(t *testing.T) {
+	type Test struct {
+		Name string `find:"name"`
+	}
+
+	index := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := index.IndexContext(ctx, "1", Test{Name: "billy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if err := index.IndexContext(ctx, "2", Test{Name: "sally"}); err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if _, err := index.Get("2"); err == nil {
+		t.Errorf("expected canceled document to not be indexed")
+	}
+}
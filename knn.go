@@ -0,0 +1,206 @@
+package gofindit
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+// KNNQuery finds the K documents with vectors most similar to Vector
+type KNNQuery struct {
+	Field  string    `json:"field"`
+	Vector []float32 `json:"vector"`
+	K      int       `json:"k"`
+
+	// NumCandidates is how many candidates the approximate search
+	// considers, more is slower but more accurate
+	NumCandidates int `json:"num_candidates"`
+
+	// Exact forces a brute force search even if the index has a HNSW graph
+	Exact bool `json:"exact"`
+}
+
+func (kq *KNNQuery) Validate() error {
+	if kq.Field == "" {
+		return fmt.Errorf("knn field cannot be empty")
+	}
+
+	if len(kq.Vector) == 0 {
+		return fmt.Errorf("knn vector cannot be empty")
+	}
+
+	if kq.K <= 0 {
+		return fmt.Errorf("knn k must be greater than 0")
+	}
+
+	if kq.NumCandidates < 0 {
+		return fmt.Errorf("knn num_candidates cannot be negative")
+	}
+
+	return nil
+}
+
+// vectorField tracks the settings every document
+// must share for a vector field in the index
+type vectorField struct {
+	dims       int
+	similarity string
+	graph      *hnsw // nil unless the index has HNSW enabled
+}
+
+// KNN returns the K documents with vectors most similar to the query
+func (i *Index) KNN(query KNNQuery) ([]any, error) {
+	return i.Search(SearchQuery{Limit: uint(query.K), KNN: &query})
+}
+
+// indexVectors checks the vector fields of a document match the
+// dims and similarity of the index and adds them to the HNSW graph
+func (i *Index) indexVectors(id string, doc *Document) error {
+	// Validate everything before adding anything
+	if err := i.checkVectors(doc); err != nil {
+		return err
+	}
+
+	for name, field := range doc.Fields {
+		vector, ok := field.(*fields.Vector)
+		if !ok {
+			continue
+		}
+
+		vf, ok := i.vectors[name]
+		if !ok {
+			// First vector for the field sets the dims and similarity
+			vf = &vectorField{dims: vector.Dims(), similarity: vector.SimilarityName()}
+			if i.HNSW != nil {
+				vf.graph = newHNSW(*i.HNSW, vf.similarity)
+			}
+			i.vectors[name] = vf
+		}
+
+		if vf.graph != nil {
+			vf.graph.add(id, vector.Vector())
+		}
+	}
+
+	return nil
+}
+
+// checkVectors returns an error if a vector field of the document does
+// not match the dims and similarity of the vectors already indexed
+func (i *Index) checkVectors(doc *Document) error {
+	for name, field := range doc.Fields {
+		vector, ok := field.(*fields.Vector)
+		if !ok {
+			continue
+		}
+
+		vf, ok := i.vectors[name]
+		if !ok {
+			continue
+		}
+		if vector.Dims() != vf.dims {
+			return fmt.Errorf("vector field %s has %d dims, expected %d", name, vector.Dims(), vf.dims)
+		}
+		if vector.SimilarityName() != vf.similarity {
+			return fmt.Errorf("vector field %s uses %s similarity, expected %s", name, vector.SimilarityName(), vf.similarity)
+		}
+	}
+
+	return nil
+}
+
+// knn returns the K documents most similar to the query out of the
+// candidates. If there is a HNSW graph the approximate search is used
+// unless the candidates were filtered down enough for brute force.
+// Candidates from a point in time must be filtered
+func (i *Index) knn(candidates []*Document, query KNNQuery, filtered bool) ([]hit, error) {
+	vf, ok := i.vectors[query.Field]
+	if !ok {
+		return nil, nil
+	}
+	if len(query.Vector) != vf.dims {
+		return nil, fmt.Errorf("knn vector has %d dims, expected %d", len(query.Vector), vf.dims)
+	}
+
+	numCandidates := query.NumCandidates
+	if numCandidates == 0 && i.HNSW != nil {
+		numCandidates = i.HNSW.sanatize().EfSearch
+	}
+
+	// Use the graph unless the filters narrowed the
+	// candidates down enough that brute force is cheaper
+	if vf.graph != nil && !query.Exact && (!filtered || len(candidates) > numCandidates) {
+		// Graph ids are resolved through the candidates when filtered so
+		// a point in time gets its own documents and not the current ones
+		documents := i.Documents
+		var allow func(id string) bool
+		if filtered {
+			documents = make(map[string]*Document, len(candidates))
+			for _, doc := range candidates {
+				documents[doc.ID] = doc
+			}
+			allow = func(id string) bool { return documents[id] != nil }
+		}
+
+		var hits []hit
+		for _, id := range vf.graph.search(query.Vector, query.K, numCandidates, allow) {
+			doc, ok := documents[id]
+			if !ok {
+				continue
+			}
+
+			// A point in time can have an older document without the vector
+			field, _ := doc.GetField(query.Field)
+			vector, ok := field.(*fields.Vector)
+			if !ok {
+				continue
+			}
+
+			score, err := vector.Similarity(query.Vector)
+			if err != nil {
+				return nil, err
+			}
+			hits = append(hits, hit{doc: doc, score: score})
+		}
+
+		// The graph can miss allowed documents it could not reach,
+		// brute force is the only way to be sure none are left
+		if len(hits) == query.K || len(hits) == len(candidates) {
+			return hits, nil
+		}
+	}
+
+	return bruteForceKNN(candidates, query)
+}
+
+// bruteForceKNN scores every candidate and returns the K most similar
+func bruteForceKNN(candidates []*Document, query KNNQuery) ([]hit, error) {
+	var hits []hit
+	for _, doc := range candidates {
+		field, ok := doc.GetField(query.Field)
+		if !ok {
+			continue
+		}
+
+		vector, ok := field.(*fields.Vector)
+		if !ok {
+			return nil, fmt.Errorf("cannot use knn search on %s type", field.Type())
+		}
+
+		score, err := vector.Similarity(query.Vector)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit{doc: doc, score: score})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+
+	if len(hits) > query.K {
+		hits = hits[:query.K]
+	}
+	return hits, nil
+}
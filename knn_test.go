@@ -0,0 +1,190 @@
+package gofindit
+
+import (
+	"fmt"
+	"testing"
+)
+
+type TestEmbedding struct {
+	Name      string    `find:"name"`
+	Category  string    `find:"category"`
+	Embedding []float32 `find:"embedding"`
+}
+
+func newTestEmbeddingIndex(t *testing.T, options Options) *Index {
+	t.Helper()
+
+	docs := make(map[string]any, 100)
+	for i := 0; i < 100; i++ {
+		category := "even"
+		if i%2 == 1 {
+			category = "odd"
+		}
+
+		docs[fmt.Sprintf("%d", i)] = TestEmbedding{
+			Name:      fmt.Sprintf("doc %d", i),
+			Category:  category,
+			Embedding: []float32{float32(i), float32(100 - i), 1},
+		}
+	}
+
+	return newTestIndex(t, NewOptions(options), docs)
+}
+
+func TestIndex_KNN(t *testing.T) {
+	for _, options := range []Options{{}, {HNSW: &HNSWOptions{}}} {
+		index := newTestEmbeddingIndex(t, options)
+
+		results, err := index.KNN(KNNQuery{
+			Field:  "embedding",
+			Vector: []float32{10, 90, 1},
+			K:      3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+
+		if results[0].(TestEmbedding).Name != "doc 10" {
+			t.Errorf("expected doc 10 first, got %s", results[0].(TestEmbedding).Name)
+		}
+	}
+}
+
+func TestIndex_Search_knnFiltered(t *testing.T) {
+	for _, options := range []Options{{}, {HNSW: &HNSWOptions{}}} {
+		index := newTestEmbeddingIndex(t, options)
+
+		// Only odd documents should come back even though doc 10 is the closest
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: "category", Type: "match", Value: "odd"}},
+			KNN: &KNNQuery{
+				Field:  "embedding",
+				Vector: []float32{10, 90, 1},
+				K:      2,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(results))
+		}
+
+		for _, result := range results {
+			if result.(TestEmbedding).Category != "odd" {
+				t.Errorf("expected only odd results, got %s", result.(TestEmbedding).Name)
+			}
+		}
+	}
+}
+
+func TestIndex_Search_knnFilteredHNSW(t *testing.T) {
+	// Only 1 in 20 documents pass the filter, so the closest ef
+	// candidates in the graph hold far fewer than k of them
+	index := NewOptions(Options{HNSW: &HNSWOptions{EfSearch: 20}})
+	for i := 0; i < 2000; i++ {
+		category := "other"
+		if i%20 == 0 {
+			category = "rare"
+		}
+
+		doc := TestEmbedding{
+			Name:      fmt.Sprintf("doc %d", i),
+			Category:  category,
+			Embedding: []float32{float32(i % 50), float32(i / 50), float32(i % 7)},
+		}
+		if err := index.Index(fmt.Sprintf("%d", i), doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search := func(exact bool) []any {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: "category", Type: "match", Value: "rare"}},
+			KNN: &KNNQuery{
+				Field:  "embedding",
+				Vector: []float32{25, 20, 3},
+				K:      10,
+				Exact:  exact,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+
+	exact, approx := search(true), search(false)
+	if len(approx) != len(exact) || len(exact) != 10 {
+		t.Fatalf("expected 10 results like exact search, got %d and exact got %d", len(approx), len(exact))
+	}
+
+	for _, result := range approx {
+		if result.(TestEmbedding).Category != "rare" {
+			t.Errorf("expected only rare results, got %s", result.(TestEmbedding).Name)
+		}
+	}
+}
+
+func TestIndex_Search_knnHNSWCompact(t *testing.T) {
+	index := newTestEmbeddingIndex(t, Options{HNSW: &HNSWOptions{}})
+	graph := index.vectors["embedding"].graph
+
+	// Updates and deletes leave deleted nodes behind until there
+	// are enough of them for the graph to be rebuilt without them
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 100; i++ {
+			doc := TestEmbedding{Name: fmt.Sprintf("doc %d", i), Embedding: []float32{float32(i), float32(100 - i), float32(round)}}
+			if err := index.Update(fmt.Sprintf("%d", i), doc); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for i := 0; i < 80; i++ {
+		if err := index.Delete(fmt.Sprintf("%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if live := len(graph.nodes) - graph.deleted; live != 20 {
+		t.Errorf("expected 20 live nodes, got %d", live)
+	}
+	if float64(graph.deleted) > float64(len(graph.nodes))*hnswCompactRatio {
+		t.Errorf("expected the graph to be compacted, got %d deleted of %d nodes", graph.deleted, len(graph.nodes))
+	}
+
+	results, err := index.KNN(KNNQuery{Field: "embedding", Vector: []float32{10, 90, 4}, K: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].(TestEmbedding).Name != "doc 80" {
+		t.Errorf("expected doc 80 to be the closest live document, got %v", results)
+	}
+}
+
+func TestIndex_knnDims(t *testing.T) {
+	index := newTestEmbeddingIndex(t, Options{})
+
+	// Documents must all have the same number of dims
+	err := index.Index("bad", TestEmbedding{Name: "bad", Embedding: []float32{1, 2}})
+	if err == nil {
+		t.Errorf("expected error indexing a vector with different dims")
+	}
+
+	// Query vectors must match the dims too
+	_, err = index.KNN(KNNQuery{Field: "embedding", Vector: []float32{1, 2}, K: 1})
+	if err == nil {
+		t.Errorf("expected error searching with a vector with different dims")
+	}
+
+	// K must be set
+	err = (&KNNQuery{Field: "embedding", Vector: []float32{1, 2, 3}}).Validate()
+	if err == nil {
+		t.Errorf("expected error for knn query without k")
+	}
+}
@@ -0,0 +1,123 @@
+package gofindit
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"time"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+// Findable lets a type control how it is indexed. FindValue returns
+// the value to index in its place, like a string, number, bool,
+// time.Time or struct, and a nil value is indexed as null
+type Findable interface {
+	FindValue() (any, error)
+}
+
+// Enum is implemented by string backed enum types to declare
+// their values, they are indexed as an enum field that only
+// accepts those values and sorts in the order they are listed
+type Enum interface {
+	EnumValues() []string
+}
+
+// nativeTypes are struct, array and slice types that
+// already have a field and should not be marshaled
+var nativeTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):       true,
+	reflect.TypeOf(netip.Addr{}):      true,
+	reflect.TypeOf(net.IP(nil)):       true,
+	reflect.TypeOf(fields.GeoPoint{}): true,
+}
+
+// marshalValue returns the value a type wants indexed in its place.
+// Findable and driver.Valuer, which covers the sql.Null types, are
+// used for any type. encoding.TextMarshaler and fmt.Stringer are only
+// used for types that could not be indexed otherwise, so basic kinds
+// keep their field type and structs with fields are still walked
+func marshalValue(val reflect.Value) (any, bool, error) {
+	if nativeTypes[val.Type()] || isGeoStruct(val.Type()) || !val.CanInterface() {
+		return nil, false, nil
+	}
+
+	// Methods can be on the value or the pointer
+	receivers := []any{val.Interface()}
+	if val.CanAddr() {
+		receivers = append(receivers, val.Addr().Interface())
+	}
+
+	if findable, ok := asInterface[Findable](receivers); ok {
+		value, err := findable.FindValue()
+		return value, true, err
+	}
+
+	if valuer, ok := asInterface[driver.Valuer](receivers); ok {
+		value, err := valuer.Value()
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		return value, true, err
+	}
+
+	if walkable(val.Type()) {
+		return nil, false, nil
+	}
+
+	if marshaler, ok := asInterface[encoding.TextMarshaler](receivers); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return nil, true, err
+		}
+		return string(text), true, nil
+	}
+
+	if stringer, ok := asInterface[fmt.Stringer](receivers); ok {
+		return stringer.String(), true, nil
+	}
+
+	return nil, false, nil
+}
+
+// walkable returns true if the structure walker can index the type,
+// which is basic kinds, structs with exported fields and slices of
+// strings or structs
+func walkable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				return true
+			}
+		}
+		return false
+	case reflect.Array, reflect.Slice:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		return elem.Kind() == reflect.String || elem.Kind() == reflect.Struct
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// asInterface returns the first receiver that implements T
+func asInterface[T any](receivers []any) (T, bool) {
+	for _, receiver := range receivers {
+		if v, ok := receiver.(T); ok {
+			return v, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
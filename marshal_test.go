@@ -0,0 +1,142 @@
+package gofindit
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TestMoney struct {
+	Cents int64
+}
+
+func (m TestMoney) FindValue() (any, error) {
+	return float64(m.Cents) / 100, nil
+}
+
+type TestUUID [4]byte
+
+func (u TestUUID) String() string {
+	return fmt.Sprintf("%x-%x", u[:2], u[2:])
+}
+
+type TestColor struct {
+	name string
+}
+
+func (c *TestColor) MarshalText() ([]byte, error) {
+	return []byte(c.name), nil
+}
+
+type TestHome struct {
+	City string `find:"city"`
+}
+
+func (a TestHome) String() string {
+	return "home:" + a.City
+}
+
+type TestTags []string
+
+func (t TestTags) String() string {
+	return "tags"
+}
+
+type TestLevel int
+
+func (l TestLevel) String() string {
+	return "level"
+}
+
+func TestMarshalValue(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		value   any
+		want    any
+		handled bool
+	}{
+		{"Findable", TestMoney{Cents: 1250}, 12.5, true},
+		{"Stringer array", TestUUID{1, 2, 3, 4}, "0102-0304", true},
+		{"TextMarshaler pointer receiver", TestColor{name: "red"}, "red", true},
+		{"Stringer basic kind", TestLevel(2), nil, false},
+		{"NullString", sql.NullString{String: "billy", Valid: true}, "billy", true},
+		{"NullString invalid", sql.NullString{}, nil, true},
+		{"NullInt64", sql.NullInt64{Int64: 10, Valid: true}, int64(10), true},
+		{"NullTime", sql.NullTime{Time: now, Valid: true}, now, true},
+		{"Time", now, nil, false},
+		{"Struct", struct{ Name string }{"billy"}, nil, false},
+		{"Stringer struct with fields", TestHome{City: "Paris"}, nil, false},
+		{"Stringer strings", TestTags{"a", "b"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Make the value addressable like it is when walking a struct
+			val := reflect.New(reflect.TypeOf(tt.value)).Elem()
+			val.Set(reflect.ValueOf(tt.value))
+
+			got, handled, err := marshalValue(val)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if handled != tt.handled {
+				t.Fatalf("marshalValue() handled = %v, want %v", handled, tt.handled)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("marshalValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStructure_marshal(t *testing.T) {
+	type Test struct {
+		Price    TestMoney      `find:"price"`
+		ID       TestUUID       `find:"id"`
+		Nickname sql.NullString `find:"nickname"`
+		Color    TestColor      `find:"color"`
+	}
+
+	s, err := newStructure(Test{Price: TestMoney{Cents: 100}, Color: TestColor{name: "red"}}, "")
+	if err != nil {
+		t.Fatalf("Failed to get structure: %v", err)
+	}
+
+	// Color only has a pointer receiver TextMarshaler
+	for _, field := range []string{"price", "id", "color"} {
+		if _, found := s.fields[field]; !found {
+			t.Errorf("Field %v not found in structure", field)
+		}
+	}
+
+	// Invalid sql null types are tracked as nulls
+	if !s.nulls["nickname"] {
+		t.Errorf("Field nickname expected to be null")
+	}
+}
+
+func TestGetStructure_marshalNestedStringer(t *testing.T) {
+	type Test struct {
+		Home  TestHome   `find:"home"`
+		Homes []TestHome `find:"homes"`
+	}
+
+	s, err := newStructure(Test{Home: TestHome{City: "Paris"}, Homes: []TestHome{{City: "Rome"}}}, "")
+	if err != nil {
+		t.Fatalf("Failed to get structure: %v", err)
+	}
+
+	// Structs with fields are walked the same on their own and in slices
+	for field, want := range map[string]string{"home.city": "Paris", "homes[0].city": "Rome"} {
+		if got, found := s.fields[field]; !found || got.Value() != want {
+			t.Errorf("expected field %s to be %s, got %v", field, want, got)
+		}
+	}
+	if _, found := s.fields["home"]; found {
+		t.Errorf("expected home to be walked instead of indexed by its String method")
+	}
+}
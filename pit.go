@@ -0,0 +1,99 @@
+package gofindit
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"time"
+)
+
+// pointInTime is a view of the documents when it was opened
+type pointInTime struct {
+	documents map[string]*Document
+	ttl       time.Duration
+	expires   time.Time
+}
+
+// OpenPIT opens a point in time of the current documents for searches
+// to use with SearchQuery.PIT. Documents indexed after it was opened are
+// not seen. It expires after the ttl, which is reset every time it is used
+func (i *Index) OpenPIT(ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", fmt.Errorf("pit ttl must be greater than 0")
+	}
+
+	// The pit is registered before the index is unlocked so a removal
+	// cannot free the doc values of documents it has in between
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	i.pitMu.Lock()
+	defer i.pitMu.Unlock()
+
+	i.expirePITs()
+
+	id := fmt.Sprintf("%016x", rand.Uint64())
+	i.pits[id] = &pointInTime{documents: maps.Clone(i.Documents), ttl: ttl, expires: time.Now().Add(ttl)}
+
+	return id, nil
+}
+
+// ClosePIT closes the point in time so its documents can be freed. Doc
+// values of documents removed while it was open are freed with the last
+func (i *Index) ClosePIT(id string) error {
+	// The index is locked first, the same as when removing documents
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.pitMu.Lock()
+	_, ok := i.pits[id]
+	delete(i.pits, id)
+	i.pitMu.Unlock()
+	if !ok {
+		return fmt.Errorf("pit %s not found", id)
+	}
+
+	i.freeRemoved()
+
+	return nil
+}
+
+// queryDocuments returns the documents at the point in
+// time of the query, or the current documents if it has none
+func (i *Index) queryDocuments(searchQuery SearchQuery) (map[string]*Document, error) {
+	if searchQuery.PIT == "" {
+		// Geo queries only need to check the documents near them. The
+		// cells are of the current documents so a point in time checks all
+		if candidates, ok := i.geoCandidates(searchQuery.Fields); ok {
+			return candidates, nil
+		}
+		return i.Documents, nil
+	}
+	return i.pitDocuments(searchQuery.PIT)
+}
+
+// pitDocuments returns the documents of the point in time and keeps it alive
+func (i *Index) pitDocuments(id string) (map[string]*Document, error) {
+	i.pitMu.Lock()
+	defer i.pitMu.Unlock()
+
+	i.expirePITs()
+
+	pit, ok := i.pits[id]
+	if !ok {
+		return nil, fmt.Errorf("pit %s not found or expired", id)
+	}
+	pit.expires = time.Now().Add(pit.ttl)
+
+	return pit.documents, nil
+}
+
+// expirePITs removes every expired point in time, pitMu must be held
+func (i *Index) expirePITs() {
+	now := time.Now()
+	for id, pit := range i.pits {
+		if now.After(pit.expires) {
+			delete(i.pits, id)
+		}
+	}
+}
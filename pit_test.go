@@ -0,0 +1,196 @@
+package gofindit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIndex_PIT(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	pit, err := index.OpenPIT(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := index.SearchHits(SearchQuery{Limit: 2, SortBy: "name", PIT: pit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.PIT != pit {
+		t.Errorf("expected pit %s in the response, got %s", pit, first.PIT)
+	}
+
+	// Documents indexed after the pit are not seen
+	if err := index.Index("adam", TestMember{Name: "adam", Team: "red"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Index("zack", TestMember{Name: "zack", Team: "red"}); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := index.SearchHits(SearchQuery{Limit: 2, SortBy: "name", PIT: pit, SearchAfter: first.Cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Total != 4 {
+		t.Errorf("expected total of 4 at the pit, got %d", second.Total)
+	}
+
+	var ids []string
+	for _, hit := range append(first.Hits, second.Hits...) {
+		ids = append(ids, hit.ID)
+	}
+	want := []string{"billy", "molly", "sally", "tommy"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("expected %s at %d, got %s", want[i], i, ids[i])
+		}
+	}
+
+	// The current documents have everything
+	current, err := index.SearchHits(SearchQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Total != 6 {
+		t.Errorf("expected total of 6, got %d", current.Total)
+	}
+
+	if err := index.ClosePIT(pit); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.SearchHits(SearchQuery{PIT: pit}); err == nil {
+		t.Errorf("expected closed pit to error")
+	}
+	if err := index.ClosePIT(pit); err == nil {
+		t.Errorf("expected closing a closed pit to error")
+	}
+}
+
+func TestIndex_PIT_expires(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	if _, err := index.OpenPIT(0); err == nil {
+		t.Errorf("expected a ttl of 0 to error")
+	}
+
+	pit, err := index.OpenPIT(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := index.SearchHits(SearchQuery{PIT: pit}); err == nil {
+		t.Errorf("expected expired pit to error")
+	}
+}
+
+func TestIndex_PIT_knn(t *testing.T) {
+	index := newTestEmbeddingIndex(t, Options{HNSW: &HNSWOptions{}})
+
+	pit, err := index.OpenPIT(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a closer document after the pit
+	if err := index.Index("new", TestEmbedding{Name: "doc new", Embedding: []float32{10, 90, 1.1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := index.SearchHits(SearchQuery{
+		PIT: pit,
+		KNN: &KNNQuery{Field: "embedding", Vector: []float32{10, 90, 1}, K: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Hits) != 3 {
+		t.Fatalf("expected 3 hits, got %d", len(response.Hits))
+	}
+	if name := response.Hits[0].Document.(TestEmbedding).Name; name != "doc 10" {
+		t.Errorf("expected doc 10 from the pit first, got %s", name)
+	}
+	for _, hit := range response.Hits {
+		if hit.ID == "new" {
+			t.Errorf("expected document indexed after the pit to not be found")
+		}
+	}
+}
+
+func TestIndex_PIT_knnUpdated(t *testing.T) {
+	index := newTestEmbeddingIndex(t, Options{HNSW: &HNSWOptions{}})
+	if err := index.Index("x", TestEmbedding{Name: "doc x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pit, err := index.OpenPIT(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The graph has x but the pit has the document without a vector
+	if err := index.Update("x", TestEmbedding{Name: "doc x", Embedding: []float32{10, 90, 1.1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := index.SearchHits(SearchQuery{
+		PIT: pit,
+		KNN: &KNNQuery{Field: "embedding", Vector: []float32{10, 90, 1}, K: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Hits) != 3 {
+		t.Fatalf("expected 3 hits, got %d", len(response.Hits))
+	}
+	for _, hit := range response.Hits {
+		if hit.ID == "x" {
+			t.Errorf("expected x without a vector at the pit to not be found")
+		}
+	}
+}
+
+func TestIndex_PIT_removed(t *testing.T) {
+	index := newTestMemberIndex(t)
+	num := index.Documents["billy"].num
+
+	first, err := index.OpenPIT(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := index.OpenPIT(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := index.Bulk([]BulkOperation{{Type: "delete", ID: "billy"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pits can still sort by the doc values of the removed document
+	response, err := index.SearchHits(SearchQuery{SortBy: "name", PIT: first})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Hits) != 4 || response.Hits[0].ID != "billy" {
+		t.Errorf("expected billy first at the pit, got %+v", response.Hits)
+	}
+
+	// Doc values are freed when the last pit closes
+	if err := index.ClosePIT(first); err != nil {
+		t.Fatal(err)
+	}
+	if index.docValues["name"].get(num) == nil {
+		t.Errorf("expected doc values to be kept while a pit is open")
+	}
+	if err := index.ClosePIT(second); err != nil {
+		t.Fatal(err)
+	}
+	if index.docValues["name"].get(num) != nil || len(index.removed) != 0 {
+		t.Errorf("expected doc values to be freed after the last pit closed")
+	}
+}
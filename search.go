@@ -1,10 +1,15 @@
 package gofindit
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"sort"
+	"reflect"
 	"strings"
 	"time"
+
+	"github.com/brianvoe/gofindit/fields"
 )
 
 type SearchQuery struct {
@@ -13,6 +18,29 @@ type SearchQuery struct {
 	Sort   string             `json:"sort"`    // asc or desc
 	SortBy string             `json:"sort_by"` // Field to sort by
 	Fields []SearchQueryField `json:"fields"`
+
+	// SortGeo sorts a geo SortBy field by distance from this point
+	SortGeo *fields.GeoPoint `json:"sort_geo"`
+
+	// Sorts are sorted by in order after SortBy, use _score
+	// and _id as tie breakers. Defaults to _score then _id
+	Sorts []SortField `json:"sorts"`
+
+	// SearchAfter is the Cursor of the last page, only hits
+	// sorted after it are returned. Cannot be used with Skip
+	SearchAfter string `json:"search_after"`
+
+	// PIT is a point in time from OpenPIT to search instead of the
+	// current documents so pages dont change between searches
+	PIT string `json:"pit"`
+
+	// Timeout stops matching documents after the duration. SearchHits
+	// returns what was found so far with TimedOut set, Search returns
+	// ErrTimedOut as it cannot tell partial results apart
+	Timeout time.Duration `json:"timeout"`
+
+	// KNN returns the nearest neighbors out of the documents matching Fields
+	KNN *KNNQuery `json:"knn"`
 }
 
 func (sq *SearchQuery) Sanatize() {
@@ -25,6 +53,11 @@ func (sq *SearchQuery) Sanatize() {
 	for i := range (*sq).Fields {
 		(*sq).Fields[i].Sanatize()
 	}
+
+	// If knn k is 0, set it to the limit
+	if (*sq).KNN != nil && (*sq).KNN.K == 0 {
+		(*sq).KNN.K = int((*sq).Limit)
+	}
 }
 
 func (sq *SearchQuery) Validate() error {
@@ -38,6 +71,39 @@ func (sq *SearchQuery) Validate() error {
 		return fmt.Errorf("sort_by cannot be set without sort")
 	}
 
+	// Make sure sortBy is set if sorting by distance
+	if sq.SortGeo != nil && sq.SortBy == "" {
+		return fmt.Errorf("sort_geo cannot be set without sort_by")
+	}
+
+	if sq.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+
+	// Cursors already skip past the last page
+	if sq.SearchAfter != "" && sq.Skip > 0 {
+		return fmt.Errorf("skip cannot be used with search_after")
+	}
+	if sq.SearchAfter != "" && sq.KNN != nil {
+		return fmt.Errorf("search_after cannot be used with knn")
+	}
+
+	// Check if the sorts are valid
+	for _, sortField := range sq.Sorts {
+		err := sortField.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check if the knn query is valid
+	if sq.KNN != nil {
+		err := sq.KNN.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check if the fields are valid
 	for _, field := range sq.Fields {
 		err := field.Validate()
@@ -49,9 +115,35 @@ func (sq *SearchQuery) Validate() error {
 	return nil
 }
 
+// SearchResponse is a ranked list of search hits
+type SearchResponse struct {
+	Total  int    `json:"total"` // Total number of matches before skip and limit
+	Hits   []Hit  `json:"hits"`
+	Cursor string `json:"cursor,omitempty"` // SearchAfter for the next page, empty if there are no hits
+	PIT    string `json:"pit,omitempty"`    // Point in time the search used
+
+	// TimedOut is true if the query timeout was reached
+	// and the hits are only from the documents matched so far
+	TimedOut bool `json:"timed_out"`
+}
+
+// Hit is a single document in a SearchResponse
+type Hit struct {
+	ID       string             `json:"id"`
+	Score    float64            `json:"score"`
+	Scores   map[string]float64 `json:"scores,omitempty"` // Score from each sub query
+	Document any                `json:"document"`
+	Version  int64              `json:"version"`
+	SeqNo    int64              `json:"seq_no"`
+}
+
+func newHit(doc *Document, score float64) Hit {
+	return Hit{ID: doc.ID, Score: score, Document: doc.Original, Version: doc.version, SeqNo: doc.seqNo}
+}
+
 type SearchQueryField struct {
 	Field string
-	Type  string // "match", "partial", "range"
+	Type  string // "match", "partial", "range", "exists", "missing", "cidr", "geo_distance", "geo_bounding_box", "geo_polygon"
 	Value any
 }
 
@@ -71,7 +163,15 @@ func (dq *SearchQueryField) Validate() error {
 	}
 
 	// Check if the type is valid
-	if dq.Type != "match" && dq.Type != "partial" && dq.Type != "range" {
+	switch dq.Type {
+	case "match", "partial", "range":
+	case "exists", "missing":
+		return nil
+	case "cidr":
+		return validateCIDRQuery(dq.Value)
+	case "geo_distance", "geo_bounding_box", "geo_polygon":
+		return validateGeoQuery(dq.Type, dq.Value)
+	default:
 		return fmt.Errorf("invalid type %s", dq.Type)
 	}
 
@@ -109,33 +209,207 @@ func (i *Index) SearchStr(search string) ([]any, error) {
 
 // Search returns a array of documents
 func (i *Index) Search(searchQuery SearchQuery) ([]any, error) {
+	return i.SearchContext(context.Background(), searchQuery)
+}
+
+// SearchContext returns a array of documents, the search stops with
+// the context error if the context is done or ErrTimedOut if the
+// query timeout is reached
+func (i *Index) SearchContext(ctx context.Context, searchQuery SearchQuery) ([]any, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	result, err := i.search(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if result.timedOut {
+		return nil, ErrTimedOut
+	}
+
+	// Loop through results and get the original document
+	var originalResults []any
+	for _, hit := range result.hits {
+		originalResults = append(originalResults, hit.doc.Original)
+	}
+
+	return originalResults, nil
+}
+
+// hit is a document and the score it got in a search
+type hit struct {
+	doc   *Document
+	score float64
+	sort  []sortValue // Values the hit was sorted by, nil for knn hits
+}
+
+// searchResult is the sorted and paged hits of a search
+type searchResult struct {
+	hits     []hit
+	total    int  // Matches before paging
+	timedOut bool // The query timeout was reached
+}
+
+// SearchHits returns the hits of the search with their scores,
+// the total number of matches and a cursor for the next page
+func (i *Index) SearchHits(searchQuery SearchQuery) (*SearchResponse, error) {
+	return i.SearchHitsContext(context.Background(), searchQuery)
+}
+
+// SearchHitsContext returns the hits of the search, the search
+// stops with the context error if the context is done
+func (i *Index) SearchHitsContext(ctx context.Context, searchQuery SearchQuery) (*SearchResponse, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	result, err := i.search(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	hits := result.hits
+
+	response := &SearchResponse{Total: result.total, Hits: make([]Hit, len(hits)), PIT: searchQuery.PIT, TimedOut: result.timedOut}
+	for n, h := range hits {
+		response.Hits[n] = newHit(h.doc, h.score)
+	}
+
+	if len(hits) > 0 && hits[len(hits)-1].sort != nil {
+		response.Cursor, err = encodeCursor(hits[len(hits)-1].sort)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// search runs the search query and returns the sorted and paged hits
+// and the total number of matches. Knn hits are scored by similarity,
+// sorted hits by their position from 1 down towards 0 and the rest by
+// their relevance to the query and their field boosts
+func (i *Index) search(ctx context.Context, searchQuery SearchQuery) (searchResult, error) {
 	// Set default values if none set
 	searchQuery.Sanatize()
 
 	// Validate the search query
 	err := searchQuery.Validate()
 	if err != nil {
-		return nil, err
+		return searchResult{}, err
+	}
+
+	results, timedOut, err := i.matches(ctx, searchQuery)
+	if err != nil {
+		return searchResult{}, err
 	}
 
-	// If sortBy is not empty, make sure it is in the fields
-	if searchQuery.SortBy != "" {
-		found := false
-		for _, field := range searchQuery.Fields {
-			if field.Field == searchQuery.SortBy {
-				found = true
-				break
+	// Sort the results
+	var hits []hit
+	var sorts []SortField
+	if searchQuery.KNN != nil {
+		// Nearest neighbors come back sorted by similarity. The graph
+		// has the current documents so a point in time is a filter
+		filtered := len(searchQuery.Fields) > 0 || searchQuery.PIT != ""
+		hits, err = i.knn(results, *searchQuery.KNN, filtered)
+		if err != nil {
+			return searchResult{}, err
+		}
+	} else {
+		// Matches are scored by how relevant the fields they matched
+		// are to the query and the boost of those fields
+		hits = make([]hit, len(results))
+		for n, doc := range results {
+			score, err := doc.score(searchQuery.Fields)
+			if err != nil {
+				return searchResult{}, err
 			}
+			hits[n] = hit{doc: doc, score: score}
 		}
 
-		if !found {
-			return nil, fmt.Errorf("sort_by field %s not found in fields", searchQuery.SortBy)
+		sorts, err = i.sortHits(hits, searchQuery.sorts())
+		if err != nil {
+			return searchResult{}, err
+		}
+
+		// Hits sorted by a field are scored by their position
+		if sorts[0].Field != "_score" {
+			for rank := range hits {
+				hits[rank].score = float64(len(hits)-rank) / float64(len(hits))
+			}
+		}
+	}
+	total := len(hits)
+
+	// Handle search after
+	if searchQuery.SearchAfter != "" {
+		hits, err = searchAfter(hits, sorts, searchQuery.SearchAfter)
+		if err != nil {
+			return searchResult{}, err
+		}
+	}
+
+	// Handle skip
+	if searchQuery.Skip > 0 {
+		if int(searchQuery.Skip) > len(hits) {
+			hits = hits[:0]
+		} else {
+			hits = hits[searchQuery.Skip:]
+		}
+	}
+
+	// Handle limit
+	if searchQuery.Limit > 0 {
+		if int(searchQuery.Limit) < len(hits) {
+			hits = hits[:searchQuery.Limit]
 		}
 	}
 
+	return searchResult{hits: hits, total: total, timedOut: timedOut}, nil
+}
+
+// ErrTimedOut is returned by searches that cannot return
+// partial results when the query timeout is reached
+var ErrTimedOut = errors.New("search timed out")
+
+// cancelCheckInterval is how many documents are matched
+// between checks of the context and query timeout
+const cancelCheckInterval = 256
+
+// matches returns every document that matches the search query fields.
+// If the query timeout is reached the documents matched so far are
+// returned and timed out is true
+func (i *Index) matches(ctx context.Context, searchQuery SearchQuery) ([]*Document, bool, error) {
+	documents, err := i.queryDocuments(searchQuery)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Range queries on fields with a column compare keys
+	// encoded once instead of once for every document
+	ranges, err := i.rangeKeys(searchQuery.Fields)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var deadline time.Time
+	if searchQuery.Timeout > 0 {
+		deadline = time.Now().Add(searchQuery.Timeout)
+	}
+
 	// Loop through docs and run search on each one and return the ones that match
 	var results []*Document
-	for _, doc := range i.Documents {
+	checked := 0
+	for _, doc := range documents {
+		// Check for cancellation every so often
+		checked++
+		if checked%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, false, err
+			}
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				return results, true, nil
+			}
+		}
+
 		searchQueryField := searchQuery.Fields
 
 		// If no fields, add document to results
@@ -144,86 +418,180 @@ func (i *Index) Search(searchQuery SearchQuery) ([]any, error) {
 			continue
 		}
 
-		// Establish matches
-		matches := 0
+		matched, err := i.isMatch(doc, searchQueryField, ranges)
+		if err != nil {
+			return nil, false, err
+		}
+		if matched {
+			results = append(results, doc)
+		}
+	}
 
-		// Loop through search query fields
-		for _, query := range searchQueryField {
-			// Get Field
-			field, found := doc.GetField(query.Field)
-			if !found {
-				// Field not found
-				continue
-			}
+	// Catch a context that was done part way through the last check
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
 
-			// Get Query Value and Type
-			queryValue := query.Value
-			queryType := query.Type
+	return results, false, nil
+}
 
-			// Check if the value matches the query
-			switch queryType {
-			case "match":
-				matched, err := isSearchMatch(value, valueType, queryValue, queryType)
-				if err != nil {
-					return nil, err
-				}
-				if matched {
-					matches++
-				}
-			case "partial":
-				matched, err := isSearchPartial(value, valueType, queryValue, queryType)
-				if err != nil {
-					return nil, err
-				}
-				if matched {
-					matches++
-				}
-			case "range":
+// isMatch returns true if the document matches every search query field
+func (i *Index) isMatch(doc *Document, searchQueryField []SearchQueryField, ranges [][2][]byte) (bool, error) {
+	// Establish matches
+	matches := 0
+
+	// Loop through search query fields
+	for q, query := range searchQueryField {
+		// Get Field
+		field, found := doc.GetField(query.Field)
+		if !found && query.Type != "missing" {
+			// Field not found
+			continue
+		}
+
+		// Get Query Value and Type
+		queryValue := query.Value
+		queryType := query.Type
 
+		// Check if the value matches the query
+		switch queryType {
+		case "match":
+			matched, err := isSearchMatch(field, queryValue)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				matches++
+			}
+		case "partial":
+			matched, err := isSearchPartial(field, queryValue)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				matches++
+			}
+		case "exists":
+			matches++
+		case "missing":
+			// Fields that were nil or not in the document
+			if !found {
+				matches++
+			}
+		case "range":
+			if keys := ranges[q]; keys[0] != nil {
+				if values := i.docValues[query.Field].get(doc.num); values != nil {
+					for _, value := range values {
+						if bytes.Compare(value.key, keys[0]) >= 0 && bytes.Compare(value.key, keys[1]) <= 0 {
+							matches++
+							break
+						}
+					}
+					break
+				}
 			}
 
-			// If matches is equal to the number of fields, then add the document to the results
-			if matches == len(searchQueryField) {
-				results = append(results, doc)
+			// Fields that range on their search bytes
+			var matched bool
+			var err error
+			switch field.Type() {
+			case fields.NumberType, fields.IPType, fields.DateType, fields.DurationType, fields.EnumType, fields.DecimalType:
+				matched, err = isSearchFieldRange(field, queryValue)
+			default:
+				err = fmt.Errorf("cannot use range search on %s field", field.Type())
+			}
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				matches++
+			}
+		case "cidr":
+			matched, err := isSearchIP(field, queryType, queryValue)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				matches++
+			}
+		case "geo_distance", "geo_bounding_box", "geo_polygon":
+			matched, err := isSearchGeo(field, queryType, queryValue)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				matches++
 			}
 		}
+
 	}
 
-	// Sort the results
-	sortOrder := searchQuery.Sort
-	sortBy := searchQuery.SortBy
-	sort.SliceStable(results, func(i, j int) bool {
-		// Sort by the sub field FieldValues
-		if sortOrder == "desc" {
-			return fmt.Sprint(results[i].Values[sortBy].Value) > fmt.Sprint(results[j].Values[sortBy].Value)
+	// If matches is equal to the number of fields, then the document matches
+	return matches == len(searchQueryField), nil
+}
+
+// rangeKeys encodes the min and max of each range query on a field
+// with a column. Queries without a column are left nil
+func (i *Index) rangeKeys(queries []SearchQueryField) ([][2][]byte, error) {
+	ranges := make([][2][]byte, len(queries))
+	for q, query := range queries {
+		if query.Type != "range" {
+			continue
 		}
 
-		return fmt.Sprint(results[i].Values[sortBy].Value) < fmt.Sprint(results[j].Values[sortBy].Value)
-	})
+		dv, ok := i.docValues[query.Field]
+		if !ok {
+			continue
+		}
+		switch dv.typ {
+		case fields.NumberType, fields.IPType, fields.DateType, fields.DurationType, fields.EnumType, fields.DecimalType:
+		default:
+			continue
+		}
 
-	// Handle skip
-	if searchQuery.Skip > 0 {
-		if int(searchQuery.Skip) > len(results) {
-			results = results[:0]
-		} else {
-			results = results[searchQuery.Skip:]
+		min, max, err := rangeValues(query.Value)
+		if err != nil {
+			return nil, err
+		}
+		if ranges[q][0], err = dv.key(min); err != nil {
+			return nil, err
+		}
+		if ranges[q][1], err = dv.key(max); err != nil {
+			return nil, err
 		}
 	}
 
-	// Handle limit
-	if searchQuery.Limit > 0 {
-		if int(searchQuery.Limit) < len(results) {
-			results = results[:searchQuery.Limit]
-		}
+	return ranges, nil
+}
+
+// rangeValues returns the min and max of a two value slice or array
+func rangeValues(queryValue any) (any, any, error) {
+	value := reflect.ValueOf(queryValue)
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || value.Len() != 2 {
+		return nil, nil, fmt.Errorf("range requires a min and max value")
 	}
 
-	// Loop through results and get the original document
-	var originalResults []any
-	for _, doc := range results {
-		originalResults = append(originalResults, doc.Original)
+	return value.Index(0).Interface(), value.Index(1).Interface(), nil
+}
+
+// isSearchFieldRange converts a two value slice or array into min and
+// max search bytes with the field and checks if the field is in range
+func isSearchFieldRange(field fields.Field, queryValue any) (bool, error) {
+	minValue, maxValue, err := rangeValues(queryValue)
+	if err != nil {
+		return false, err
 	}
 
-	return originalResults, nil
+	min, err := field.ToSearchBytes(minValue)
+	if err != nil {
+		return false, err
+	}
+	max, err := field.ToSearchBytes(maxValue)
+	if err != nil {
+		return false, err
+	}
+
+	return field.SearchRange(min, max)
 }
 
 // intersection returns the intersection of two arrays
@@ -248,113 +616,47 @@ func intersection(a []int, b []int) []int {
 	return r
 }
 
-// searchMatch checks if the value matches the query value
-func isSearchMatch(value any, valueType string, queryValue any, queryValueType string) (bool, error) {
-	// If the value type and query value type are the same, then just compare them
-	if (valueType == "string" && queryValueType == "string") ||
-		(valueType == "bool" && queryValueType == "bool") ||
-		(valueType == "int" && queryValueType == "int") ||
-		(valueType == "uint" && queryValueType == "uint") ||
-		(valueType == "float" && queryValueType == "float") {
-		return value == queryValue, nil
+// isSearchMatch converts the query value into search bytes with the
+// field and checks if the field matches them. Text fields, including
+// multi fields like name.ngram, run the value through their analyzer
+func isSearchMatch(field fields.Field, queryValue any) (bool, error) {
+	searchBytes, err := field.ToSearchBytes(queryValue)
+	if str, ok := queryValue.(string); ok && err != nil {
+		// Values from a query string are strings so
+		// try them as the type they look like
+		typedValue, typedErr := stringToAny(str)
+		if typedErr == nil {
+			searchBytes, err = field.ToSearchBytes(typedValue)
+		}
+	}
+	if err != nil {
+		return false, err
 	}
 
-	// TODO: Handle various types of arrays and mix valuetype scenarios
-	// Otherwise, convert the value to a string and compare
-	return fmt.Sprint(value) == fmt.Sprint(queryValue), nil
+	return field.Search(searchBytes)
 }
 
-func isSearchPartial(value any, valueType string, queryValue any, queryValueType string) (bool, error) {
-	// if bool or time then just continue
-	if valueType == "bool" || valueType == "time" {
-		return false, fmt.Errorf("cannot use partial search on %s type", valueType)
+// isSearchPartial checks if the field value, or any of
+// its values, contains the query value as a string
+func isSearchPartial(field fields.Field, queryValue any) (bool, error) {
+	// Partial only makes sense for text and numbers
+	switch field.Type() {
+	case fields.TextType, fields.KeywordType, fields.NumberType:
+	default:
+		return false, fmt.Errorf("cannot use partial search on %s field", field.Type())
 	}
 
-	// Convert both values to string and then do strings.Contains
-	switch valueType {
-	// Any numbers convert to string and then do strings.Contains
-	case "string", "int", "uint", "float":
-		if strings.Contains(fmt.Sprint(value), fmt.Sprint(queryValue)) {
-			return true, nil
-		}
-	case "[]string", "[]int", "[]uint", "[]float":
+	query := fmt.Sprint(queryValue)
+	value := reflect.ValueOf(field.Value())
+	if value.Kind() == reflect.Slice {
 		// Loop through array and check if any of the values match
-		for _, v := range value.([]any) {
-			if strings.Contains(fmt.Sprint(v), fmt.Sprint(queryValue)) {
+		for j := 0; j < value.Len(); j++ {
+			if strings.Contains(fmt.Sprint(value.Index(j).Interface()), query) {
 				return true, nil // If one value matches, break the loop
 			}
 		}
+		return false, nil
 	}
 
-	return false, nil
-}
-
-func isSearchRange(value any, valueType string, queryValue any, queryValueType string) (bool, error) {
-	// If valueType is not a number, then continue
-	if valueType == "string" || valueType == "bool" {
-		return false, fmt.Errorf("cannot use range search on %s type", valueType)
-	}
-
-	// Initial declarations for min and max values for both numeric and time ranges
-	var minNum, maxNum float64
-	var minTime, maxTime time.Time
-	var err error
-	var isTimeRange, isNumRange bool
-
-	// Determine the type of range query: Time or Numeric based on valueType
-	switch valueType {
-	case "time":
-		isTimeRange = true
-		// Handle time ranges
-		if timeRange, ok := queryValue.([]time.Time); ok && len(timeRange) > 0 {
-			minTime = timeRange[0]
-			if len(timeRange) > 1 {
-				maxTime = timeRange[1]
-			}
-		} else if singleTime, ok := queryValue.(time.Time); ok {
-			minTime = singleTime
-			// For single time, consider maxTime as zero value of time.Time, indicating no end range
-		}
-	case "int", "uint", "float":
-		isNumRange = true
-		// Handle numeric ranges
-		if numRange, ok := queryValue.([]time.Time); ok && len(numRange) > 0 {
-			minNum, err = toFloat64(numRange[0])
-			if err != nil {
-				return false, err // Skip if conversion fails
-			}
-			if len(numRange) > 1 {
-				maxNum, err = toFloat64(numRange[1])
-				if err != nil {
-					return false, err // Skip if conversion fails
-				}
-			}
-		} else if singleNum, ok := queryValue.(float64); ok {
-			minNum = singleNum
-			// For single number, consider maxNum as not set
-		}
-	}
-
-	// Compare based on determined range type
-	if isTimeRange {
-		fieldValue := value.(time.Time)
-		if !minTime.IsZero() && fieldValue.Before(minTime) {
-			return false, nil
-		}
-		if !maxTime.IsZero() && fieldValue.After(maxTime) {
-			return false, nil
-		}
-		return true, nil
-	} else if isNumRange {
-		fieldValue, err := toFloat64(value)
-		if err != nil {
-			return false, err // Skip if conversion fails
-		}
-		if fieldValue < minNum || (!isZero(maxNum) && fieldValue > maxNum) {
-			return false, nil
-		}
-		return true, nil
-	}
-
-	return false, fmt.Errorf("invalid range search type %s", valueType)
+	return strings.Contains(fmt.Sprint(field.Value()), query), nil
 }
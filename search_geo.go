@@ -0,0 +1,428 @@
+package gofindit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+// GeoDistanceQuery matches geo points within Distance meters of Point
+type GeoDistanceQuery struct {
+	Point    fields.GeoPoint `json:"point"`
+	Distance float64         `json:"distance"` // In meters
+}
+
+// UnmarshalJSON allows distance to be a number of
+// meters or a string with a unit like "10km"
+func (gq *GeoDistanceQuery) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Point    fields.GeoPoint `json:"point"`
+		Distance any             `json:"distance"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	gq.Point = raw.Point
+	switch distance := raw.Distance.(type) {
+	case float64:
+		gq.Distance = distance
+	case string:
+		meters, err := ParseDistance(distance)
+		if err != nil {
+			return err
+		}
+		gq.Distance = meters
+	default:
+		return fmt.Errorf("invalid distance %v", raw.Distance)
+	}
+
+	return nil
+}
+
+// GeoBoundingBoxQuery matches geo points inside the box
+type GeoBoundingBoxQuery struct {
+	TopLeft     fields.GeoPoint `json:"top_left"`
+	BottomRight fields.GeoPoint `json:"bottom_right"`
+}
+
+// GeoPolygonQuery matches geo points inside the polygon
+type GeoPolygonQuery struct {
+	Points []fields.GeoPoint `json:"points"`
+}
+
+// distanceUnits are the number of meters in each unit
+var distanceUnits = map[string]float64{
+	"mm": 0.001, "cm": 0.01, "m": 1, "km": 1000,
+	"in": 0.0254, "ft": 0.3048, "yd": 0.9144, "mi": 1609.344, "nmi": 1852,
+}
+
+// ParseDistance parses a distance like "500m", "10km" or
+// "2.5mi" into meters. A number without a unit is meters
+func ParseDistance(distance string) (float64, error) {
+	distance = strings.TrimSpace(strings.ToLower(distance))
+
+	// Find where the number ends and the unit starts
+	end := strings.LastIndexAny(distance, "0123456789.") + 1
+	number, unit := distance[:end], strings.TrimSpace(distance[end:])
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid distance %s", distance)
+	}
+
+	if unit == "" {
+		return value, nil
+	}
+
+	multiplier, ok := distanceUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid distance unit %s", unit)
+	}
+
+	return value * multiplier, nil
+}
+
+// toGeoQuery converts a query value into the geo query type,
+// values from json will come in as a map and are converted over
+func toGeoQuery[T any](value any) (T, error) {
+	var query T
+
+	switch v := value.(type) {
+	case T:
+		return v, nil
+	case *T:
+		if v != nil {
+			return *v, nil
+		}
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return query, err
+		}
+		err = json.Unmarshal(data, &query)
+		return query, err
+	}
+
+	return query, fmt.Errorf("invalid geo query value %T", value)
+}
+
+// validateGeoQuery checks the value is valid for the geo query type
+func validateGeoQuery(queryType string, queryValue any) error {
+	switch queryType {
+	case "geo_distance":
+		query, err := toGeoQuery[GeoDistanceQuery](queryValue)
+		if err != nil {
+			return err
+		}
+		if query.Distance <= 0 {
+			return fmt.Errorf("geo_distance distance must be greater than 0")
+		}
+	case "geo_bounding_box":
+		query, err := toGeoQuery[GeoBoundingBoxQuery](queryValue)
+		if err != nil {
+			return err
+		}
+		if query.TopLeft.Lat < query.BottomRight.Lat {
+			return fmt.Errorf("geo_bounding_box top_left must be above bottom_right")
+		}
+	case "geo_polygon":
+		query, err := toGeoQuery[GeoPolygonQuery](queryValue)
+		if err != nil {
+			return err
+		}
+		if len(query.Points) < 3 {
+			return fmt.Errorf("geo_polygon requires at least 3 points")
+		}
+	}
+
+	return nil
+}
+
+// isSearchGeo checks if the geo field matches the geo query
+func isSearchGeo(field fields.Field, queryType string, queryValue any) (bool, error) {
+	geo, ok := field.(*fields.Geo)
+	if !ok {
+		return false, fmt.Errorf("cannot use %s search on %s type", queryType, field.Type())
+	}
+
+	switch queryType {
+	case "geo_distance":
+		query, err := toGeoQuery[GeoDistanceQuery](queryValue)
+		if err != nil {
+			return false, err
+		}
+		return geo.InDistance(query.Point, query.Distance), nil
+	case "geo_bounding_box":
+		query, err := toGeoQuery[GeoBoundingBoxQuery](queryValue)
+		if err != nil {
+			return false, err
+		}
+		return geo.InBoundingBox(query.TopLeft, query.BottomRight), nil
+	case "geo_polygon":
+		query, err := toGeoQuery[GeoPolygonQuery](queryValue)
+		if err != nil {
+			return false, err
+		}
+		return geo.InPolygon(query.Points), nil
+	}
+
+	return false, fmt.Errorf("invalid geo search type %s", queryType)
+}
+
+// geoIndexPrecision is the longest geohash prefix kept for each geo field,
+// a 6 character cell is roughly 1.2km x 0.6km
+const geoIndexPrecision = 6
+
+// geoMaxCells is the most cells a query will look up
+// before it falls back to a shorter prefix
+const geoMaxCells = 64
+
+// geoCells maps each geohash prefix of a geo field to the ids of the
+// documents in that cell, so geo queries only check nearby documents
+type geoCells map[string]map[string]bool
+
+// geoBox is a latitude and longitude range in degrees
+type geoBox struct {
+	minLat, maxLat float64
+	minLon, maxLon float64
+}
+
+// indexGeo adds the geohash prefixes of the document geo fields
+func (i *Index) indexGeo(id string, doc *Document) {
+	for name, field := range doc.Fields {
+		geo, ok := field.(*fields.Geo)
+		if !ok {
+			continue
+		}
+
+		cells, ok := i.geoCells[name]
+		if !ok {
+			cells = make(geoCells)
+			i.geoCells[name] = cells
+		}
+
+		hash := geo.Geohash(geoIndexPrecision)
+		for p := 1; p <= len(hash); p++ {
+			if cells[hash[:p]] == nil {
+				cells[hash[:p]] = make(map[string]bool)
+			}
+			cells[hash[:p]][id] = true
+		}
+	}
+}
+
+// removeGeo removes the document from the cells of its geo fields
+func (i *Index) removeGeo(id string, doc *Document) {
+	for name, field := range doc.Fields {
+		geo, ok := field.(*fields.Geo)
+		if !ok {
+			continue
+		}
+
+		cells := i.geoCells[name]
+		hash := geo.Geohash(geoIndexPrecision)
+		for p := 1; p <= len(hash); p++ {
+			delete(cells[hash[:p]], id)
+			if len(cells[hash[:p]]) == 0 {
+				delete(cells, hash[:p])
+			}
+		}
+	}
+}
+
+// geoCandidates returns the documents in the geohash cells that cover
+// the first geo query. False is returned if there is no geo query
+// and every document has to be checked
+func (i *Index) geoCandidates(queries []SearchQueryField) (map[string]*Document, bool) {
+	for _, query := range queries {
+		cells, ok := i.geoCells[query.Field]
+		if !ok {
+			continue
+		}
+
+		boxes, ok := geoQueryBoxes(query.Type, query.Value)
+		if !ok {
+			continue
+		}
+
+		candidates := make(map[string]*Document)
+		for _, cell := range geoCover(boxes) {
+			for id := range cells[cell] {
+				candidates[id] = i.Documents[id]
+			}
+		}
+		return candidates, true
+	}
+
+	return nil, false
+}
+
+// geoQueryBoxes returns the boxes that contain every point the geo query
+// can match. Boxes that cross the dateline are split in two
+func geoQueryBoxes(queryType string, queryValue any) ([]geoBox, bool) {
+	var box geoBox
+
+	switch queryType {
+	case "geo_distance":
+		query, err := toGeoQuery[GeoDistanceQuery](queryValue)
+		if err != nil {
+			return nil, false
+		}
+
+		// The distance as an angle, and the widest the longitude
+		// gets at the center latitude for that angle
+		angle := query.Distance / fields.EarthRadius * 180 / math.Pi
+		box = geoBox{
+			minLat: query.Point.Lat - angle, maxLat: query.Point.Lat + angle,
+			minLon: -180, maxLon: 180,
+		}
+		if box.minLat > -90 && box.maxLat < 90 {
+			spread := math.Sin(angle*math.Pi/180) / math.Cos(query.Point.Lat*math.Pi/180)
+			if spread < 1 {
+				lonAngle := math.Asin(spread) * 180 / math.Pi
+				box.minLon, box.maxLon = query.Point.Lon-lonAngle, query.Point.Lon+lonAngle
+			}
+		}
+	case "geo_bounding_box":
+		query, err := toGeoQuery[GeoBoundingBoxQuery](queryValue)
+		if err != nil {
+			return nil, false
+		}
+		box = geoBox{
+			minLat: query.BottomRight.Lat, maxLat: query.TopLeft.Lat,
+			minLon: query.TopLeft.Lon, maxLon: query.BottomRight.Lon,
+		}
+		if box.minLon > box.maxLon {
+			box.maxLon += 360
+		}
+	case "geo_polygon":
+		query, err := toGeoQuery[GeoPolygonQuery](queryValue)
+		if err != nil || len(query.Points) == 0 {
+			return nil, false
+		}
+		box = geoBox{minLat: 90, maxLat: -90, minLon: 180, maxLon: -180}
+		for _, point := range query.Points {
+			box.minLat, box.maxLat = math.Min(box.minLat, point.Lat), math.Max(box.maxLat, point.Lat)
+			box.minLon, box.maxLon = math.Min(box.minLon, point.Lon), math.Max(box.maxLon, point.Lon)
+		}
+	default:
+		return nil, false
+	}
+
+	box.minLat, box.maxLat = math.Max(box.minLat, -90), math.Min(box.maxLat, 90)
+	if box.maxLon-box.minLon >= 360 {
+		return []geoBox{{minLat: box.minLat, maxLat: box.maxLat, minLon: -180, maxLon: 180}}, true
+	}
+
+	// Split the parts past the dateline into their own box
+	switch {
+	case box.minLon < -180:
+		west, east := box, box
+		west.minLon, west.maxLon = box.minLon+360, 180
+		east.minLon = -180
+		return []geoBox{west, east}, true
+	case box.maxLon > 180:
+		west, east := box, box
+		west.maxLon = 180
+		east.minLon, east.maxLon = -180, box.maxLon-360
+		return []geoBox{west, east}, true
+	}
+
+	return []geoBox{box}, true
+}
+
+// geoCover returns the geohash cells that cover the boxes using
+// the longest prefix that needs no more than geoMaxCells cells
+func geoCover(boxes []geoBox) []string {
+	for precision := geoIndexPrecision; precision > 1; precision-- {
+		if cells, ok := geoCoverPrecision(boxes, precision); ok {
+			return cells
+		}
+	}
+
+	// There are only 32 cells with a 1 character prefix
+	cells, _ := geoCoverPrecision(boxes, 1)
+	return cells
+}
+
+// geoCoverPrecision returns the cells of the given precision that
+// cover the boxes, false if more than geoMaxCells are needed
+func geoCoverPrecision(boxes []geoBox, precision int) ([]string, bool) {
+	// Geohashes start with longitude so it gets the extra bit
+	bits := precision * 5
+	lonSize := 360 / math.Exp2(float64((bits+1)/2))
+	latSize := 180 / math.Exp2(float64(bits/2))
+
+	var cells []string
+	for _, box := range boxes {
+		minRow, maxRow := math.Floor((box.minLat+90)/latSize), math.Floor((box.maxLat+90)/latSize)
+		minCol, maxCol := math.Floor((box.minLon+180)/lonSize), math.Floor((box.maxLon+180)/lonSize)
+		if len(cells)+int((maxRow-minRow+1)*(maxCol-minCol+1)) > geoMaxCells && precision > 1 {
+			return nil, false
+		}
+
+		for row := minRow; row <= maxRow; row++ {
+			for col := minCol; col <= maxCol; col++ {
+				// Hash the center of the cell so edges never land in a neighbor
+				center := fields.GeoPoint{
+					Lat: math.Min((row+0.5)*latSize-90, 90),
+					Lon: math.Min((col+0.5)*lonSize-180, 180),
+				}
+				cells = append(cells, fields.Geohash(center, precision))
+			}
+		}
+	}
+
+	return cells, true
+}
+
+// GeohashGrid counts the documents matching the search query fields
+// in each geohash cell of the given precision for the geo field
+func (i *Index) GeohashGrid(searchQuery SearchQuery, field string, precision int) (map[string]int, error) {
+	if precision < 1 || precision > 12 {
+		return nil, fmt.Errorf("geohash precision must be between 1 and 12")
+	}
+
+	// Set default values if none set
+	searchQuery.Sanatize()
+
+	// Validate the search query
+	err := searchQuery.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	results, timedOut, err := i.matches(context.Background(), searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if timedOut {
+		return nil, ErrTimedOut
+	}
+
+	buckets := make(map[string]int)
+	for _, doc := range results {
+		f, ok := doc.GetField(field)
+		if !ok {
+			continue
+		}
+
+		geo, ok := f.(*fields.Geo)
+		if !ok {
+			return nil, fmt.Errorf("field %s is not a geo field", field)
+		}
+
+		buckets[geo.Geohash(precision)]++
+	}
+
+	return buckets, nil
+}
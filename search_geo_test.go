@@ -0,0 +1,248 @@
+package gofindit
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+type TestStore struct {
+	Name     string     `find:"name"`
+	Location TestGeo    `find:"location"`
+	Pickup   [2]float64 `find:"pickup" field:"geo"`
+}
+
+type TestGeo struct {
+	Lat float64 `find:"lat"`
+	Lon float64 `find:"lon"`
+}
+
+func newTestStoreIndex(t *testing.T) *Index {
+	t.Helper()
+
+	return newTestIndex(t, New(), map[string]any{
+		"nyc":    TestStore{Name: "New York", Location: TestGeo{Lat: 40.7128, Lon: -74.0060}, Pickup: [2]float64{40.7128, -74.0060}},
+		"bk":     TestStore{Name: "Brooklyn", Location: TestGeo{Lat: 40.6782, Lon: -73.9442}, Pickup: [2]float64{40.6782, -73.9442}},
+		"london": TestStore{Name: "London", Location: TestGeo{Lat: 51.5074, Lon: -0.1278}, Pickup: [2]float64{51.5074, -0.1278}},
+	})
+}
+
+func TestParseDistance(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"100", 100, false},
+		{"500m", 500, false},
+		{"10km", 10000, false},
+		{"2.5 km", 2500, false},
+		{"1mi", 1609.344, false},
+		{"10parsecs", 0, true},
+		{"km", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDistance(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDistance(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDistance(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIndex_Search_geo(t *testing.T) {
+	index := newTestStoreIndex(t)
+
+	tests := []struct {
+		name  string
+		query SearchQueryField
+		want  int
+	}{
+		{
+			name: "distance",
+			query: SearchQueryField{
+				Field: "location",
+				Type:  "geo_distance",
+				Value: GeoDistanceQuery{Point: fields.GeoPoint{Lat: 40.7128, Lon: -74.0060}, Distance: 20000},
+			},
+			want: 2,
+		},
+		{
+			name: "distance from json",
+			query: SearchQueryField{
+				Field: "pickup",
+				Type:  "geo_distance",
+				Value: map[string]any{"point": map[string]any{"lat": 51.5, "lon": -0.12}, "distance": "5km"},
+			},
+			want: 1,
+		},
+		{
+			name: "bounding box",
+			query: SearchQueryField{
+				Field: "location",
+				Type:  "geo_bounding_box",
+				Value: GeoBoundingBoxQuery{
+					TopLeft:     fields.GeoPoint{Lat: 41, Lon: -75},
+					BottomRight: fields.GeoPoint{Lat: 40.7, Lon: -73},
+				},
+			},
+			want: 1,
+		},
+		{
+			name: "polygon",
+			query: SearchQueryField{
+				Field: "location",
+				Type:  "geo_polygon",
+				Value: GeoPolygonQuery{Points: []fields.GeoPoint{
+					{Lat: 52, Lon: -1}, {Lat: 52, Lon: 1}, {Lat: 51, Lon: 1}, {Lat: 51, Lon: -1},
+				}},
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Search(SearchQuery{Fields: []SearchQueryField{tt.query}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != tt.want {
+				t.Errorf("expected %d results, got %d", tt.want, len(results))
+			}
+		})
+	}
+}
+
+func TestIndex_Search_geoSort(t *testing.T) {
+	index := newTestStoreIndex(t)
+
+	// Sort everything within 10,000km by distance from london
+	london := fields.GeoPoint{Lat: 51.5074, Lon: -0.1278}
+	results, err := index.Search(SearchQuery{
+		SortBy:  "location",
+		SortGeo: &london,
+		Fields: []SearchQueryField{{
+			Field: "location",
+			Type:  "geo_distance",
+			Value: GeoDistanceQuery{Point: london, Distance: 10000000},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if results[0].(TestStore).Name != "London" {
+		t.Errorf("expected London first, got %s", results[0].(TestStore).Name)
+	}
+}
+
+func TestIndex_geoCandidates(t *testing.T) {
+	// Points spread over the whole world
+	index := New()
+	r := rand.New(rand.NewPCG(1, 2))
+	for n := 0; n < 2000; n++ {
+		store := TestStore{Location: TestGeo{Lat: r.Float64()*180 - 90, Lon: r.Float64()*360 - 180}}
+		if err := index.Index(fmt.Sprint(n), store); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queries := []SearchQueryField{
+		{Field: "location", Type: "geo_distance", Value: GeoDistanceQuery{Point: fields.GeoPoint{Lat: 40, Lon: -74}, Distance: 1500000}},
+		{Field: "location", Type: "geo_distance", Value: GeoDistanceQuery{Point: fields.GeoPoint{Lat: 10, Lon: 179}, Distance: 2000000}},
+		{Field: "location", Type: "geo_distance", Value: GeoDistanceQuery{Point: fields.GeoPoint{Lat: 85, Lon: 0}, Distance: 1000000}},
+		{Field: "location", Type: "geo_bounding_box", Value: GeoBoundingBoxQuery{
+			TopLeft: fields.GeoPoint{Lat: 30, Lon: 170}, BottomRight: fields.GeoPoint{Lat: -30, Lon: -170},
+		}},
+		{Field: "location", Type: "geo_polygon", Value: GeoPolygonQuery{Points: []fields.GeoPoint{
+			{Lat: 0, Lon: 0}, {Lat: 20, Lon: 10}, {Lat: 0, Lon: 20},
+		}}},
+	}
+
+	for _, query := range queries {
+		t.Run(query.Type, func(t *testing.T) {
+			candidates, ok := index.geoCandidates([]SearchQueryField{query})
+			if !ok {
+				t.Fatal("expected geo candidates")
+			}
+			if len(candidates) >= len(index.Documents) {
+				t.Errorf("expected fewer candidates than documents, got %d", len(candidates))
+			}
+
+			// Every document that matches has to be a candidate
+			for id, doc := range index.Documents {
+				field, _ := doc.GetField("location")
+				matched, err := isSearchGeo(field, query.Type, query.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := candidates[id]; matched && !ok {
+					t.Errorf("document %s matches but is not a candidate", id)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_GeohashGrid(t *testing.T) {
+	index := newTestStoreIndex(t)
+
+	buckets, err := index.GeohashGrid(SearchQuery{}, "location", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// New York and Brooklyn share the dr cell
+	if buckets["dr"] != 2 {
+		t.Errorf("expected 2 documents in dr, got %d", buckets["dr"])
+	}
+	if buckets["gc"] != 1 {
+		t.Errorf("expected 1 document in gc, got %d", buckets["gc"])
+	}
+
+	if _, err := index.GeohashGrid(SearchQuery{}, "location", 13); err == nil {
+		t.Errorf("expected error for invalid precision")
+	}
+}
+
+func TestIndex_geoCandidates_bulk(t *testing.T) {
+	index := New()
+	_, err := index.Bulk([]BulkOperation{
+		{Type: "index", ID: "nyc", Document: TestStore{Location: TestGeo{Lat: 40.7, Lon: -74}}},
+		{Type: "index", ID: "gone", Document: TestStore{Location: TestGeo{Lat: 40.7, Lon: -74}}},
+		{Type: "upsert", ID: "nyc", Document: TestStore{Location: TestGeo{Lat: 51.5, Lon: -0.1}}},
+		{Type: "delete", ID: "gone"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cells move with upserts and are removed with deletes
+	near := func(point fields.GeoPoint) int {
+		results, err := index.Search(SearchQuery{Fields: []SearchQueryField{
+			{Field: "location", Type: "geo_distance", Value: GeoDistanceQuery{Point: point, Distance: 10000}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(results)
+	}
+	if got := near(fields.GeoPoint{Lat: 40.7, Lon: -74}); got != 0 {
+		t.Errorf("expected no documents left in new york, got %d", got)
+	}
+	if got := near(fields.GeoPoint{Lat: 51.5, Lon: -0.1}); got != 1 {
+		t.Errorf("expected 1 document in london, got %d", got)
+	}
+}
@@ -0,0 +1,32 @@
+package gofindit
+
+import (
+	"fmt"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+// validateCIDRQuery checks the value is a valid cidr
+func validateCIDRQuery(queryValue any) error {
+	_, err := fields.ToPrefix(queryValue)
+	return err
+}
+
+// isSearchIP checks if the ip field matches the cidr query
+func isSearchIP(field fields.Field, queryType string, queryValue any) (bool, error) {
+	ip, ok := field.(*fields.IP)
+	if !ok {
+		return false, fmt.Errorf("cannot use %s search on %s type", queryType, field.Type())
+	}
+
+	switch queryType {
+	case "cidr":
+		prefix, err := fields.ToPrefix(queryValue)
+		if err != nil {
+			return false, err
+		}
+		return ip.InCIDR(prefix), nil
+	}
+
+	return false, fmt.Errorf("invalid ip search type %s", queryType)
+}
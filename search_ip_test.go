@@ -0,0 +1,132 @@
+package gofindit
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+)
+
+type TestLog struct {
+	Message string     `find:"message"`
+	Client  netip.Addr `find:"client"`
+	Server  net.IP     `find:"server"`
+	Proxy   string     `find:"proxy" field:"ip"`
+}
+
+func newTestLogIndex(t *testing.T) *Index {
+	t.Helper()
+
+	return newTestIndex(t, New(), map[string]any{
+		"a": TestLog{Message: "a", Client: netip.MustParseAddr("10.0.0.10"), Server: net.ParseIP("192.168.1.1"), Proxy: "172.16.0.1"},
+		"b": TestLog{Message: "b", Client: netip.MustParseAddr("10.0.0.9"), Server: net.ParseIP("192.168.2.1"), Proxy: "172.16.0.2"},
+		"c": TestLog{Message: "c", Client: netip.MustParseAddr("11.0.0.1"), Server: net.ParseIP("192.168.1.2")},
+		"d": TestLog{Message: "d", Client: netip.MustParseAddr("2001:db8::1")},
+	})
+}
+
+func TestIndex_Search_cidr(t *testing.T) {
+	index := newTestLogIndex(t)
+
+	tests := []struct {
+		field string
+		cidr  any
+		want  int
+	}{
+		{"client", "10.0.0.0/8", 2},
+		{"client", netip.MustParsePrefix("2001:db8::/32"), 1},
+		{"server", "192.168.1.0/24", 2},
+		{"proxy", "172.16.0.0/12", 2},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: tt.field, Type: "cidr", Value: tt.cidr}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != tt.want {
+			t.Errorf("expected %d results for %s in %v, got %d", tt.want, tt.field, tt.cidr, len(results))
+		}
+	}
+}
+
+func TestIndex_Search_ipMatch(t *testing.T) {
+	index := newTestLogIndex(t)
+
+	tests := []struct {
+		name  string
+		field string
+		ip    any
+		want  int
+	}{
+		{"ipv4", "client", "10.0.0.10", 1},
+		{"non canonical ipv6", "client", "2001:0db8:0000:0000:0000:0000:0000:0001", 1},
+		{"ipv4 mapped ipv6", "client", "::ffff:10.0.0.10", 1},
+		{"ipv4 mapped addr", "server", netip.MustParseAddr("::ffff:192.168.1.1"), 1},
+		{"net.IP", "proxy", net.ParseIP("172.16.0.2"), 1},
+		{"no match", "client", "10.0.0.11", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Search(SearchQuery{
+				Fields: []SearchQueryField{{Field: tt.field, Type: "match", Value: tt.ip}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != tt.want {
+				t.Errorf("expected %d results for %s %v, got %d", tt.want, tt.field, tt.ip, len(results))
+			}
+		})
+	}
+}
+
+func TestIndex_Search_ipRange(t *testing.T) {
+	index := newTestLogIndex(t)
+
+	results, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "client", Type: "range", Value: []string{"10.0.0.1", "10.0.0.100"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+}
+
+func TestIndex_Search_sortIP(t *testing.T) {
+	index := newTestLogIndex(t)
+
+	results, err := index.Search(SearchQuery{
+		Sort:   "asc",
+		SortBy: "client",
+		Fields: []SearchQueryField{{Field: "client", Type: "cidr", Value: "0.0.0.0/0"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10.0.0.9 sorts before 10.0.0.10 in numeric order
+	want := []string{"b", "a", "c"}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if result.(TestLog).Message != want[i] {
+			t.Errorf("expected %s at %d, got %s", want[i], i, result.(TestLog).Message)
+		}
+	}
+}
+
+func TestSearchQueryField_Validate_cidr(t *testing.T) {
+	query := SearchQueryField{Field: "client", Type: "cidr", Value: "10.0.0.0/33"}
+	if err := query.Validate(); err == nil {
+		t.Errorf("expected invalid cidr to error")
+	}
+}
@@ -0,0 +1,166 @@
+package gofindit
+
+import (
+	"context"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// iterBatchSize is how many documents are matched each time
+// the index is locked while streaming search hits
+const iterBatchSize = 256
+
+// SearchIter streams the hits of the search. A Limit of 0 streams every
+// match, other than for knn searches. Without a sort, matches are found
+// lazily in id order and nothing more is matched once the loop breaks.
+// With a sort, every match is sorted before the first hit. If the context
+// is done the context error is yielded and the iterator stops. If the
+// query timeout is reached only the hits matched so far are yielded, the
+// same as a search that returns with TimedOut set
+func (i *Index) SearchIter(ctx context.Context, searchQuery SearchQuery) iter.Seq2[Hit, error] {
+	return func(yield func(Hit, error) bool) {
+		// Zero is no limit instead of the default, knn still needs a k
+		if searchQuery.Limit == 0 && searchQuery.KNN == nil {
+			searchQuery.Limit = math.MaxInt
+		}
+
+		if searchQuery.KNN != nil || searchQuery.SearchAfter != "" || len(searchQuery.sorts()) > 0 {
+			i.searchIterSorted(ctx, searchQuery, yield)
+			return
+		}
+		i.searchIterLazy(ctx, searchQuery, yield)
+	}
+}
+
+// searchIterSorted yields every hit from a normal search
+func (i *Index) searchIterSorted(ctx context.Context, searchQuery SearchQuery, yield func(Hit, error) bool) {
+	i.mu.RLock()
+	result, err := i.search(ctx, searchQuery)
+	i.mu.RUnlock()
+	if err != nil {
+		yield(Hit{}, err)
+		return
+	}
+
+	for _, h := range result.hits {
+		if err := ctx.Err(); err != nil {
+			yield(Hit{}, err)
+			return
+		}
+		if !yield(newHit(h.doc, h.score), nil) {
+			return
+		}
+	}
+}
+
+// searchIterLazy matches the documents in id order a batch at a time.
+// The index is only locked while matching so the loop can write to it
+func (i *Index) searchIterLazy(ctx context.Context, searchQuery SearchQuery, yield func(Hit, error) bool) {
+	searchQuery.Sanatize()
+	err := searchQuery.Validate()
+	if err != nil {
+		yield(Hit{}, err)
+		return
+	}
+
+	i.mu.RLock()
+	documents, err := i.queryDocuments(searchQuery)
+	var ranges [][2][]byte
+	if err == nil {
+		ranges, err = i.rangeKeys(searchQuery.Fields)
+	}
+	docs := sortedDocuments(documents)
+	i.mu.RUnlock()
+	if err != nil {
+		yield(Hit{}, err)
+		return
+	}
+
+	var deadline time.Time
+	if searchQuery.Timeout > 0 {
+		deadline = time.Now().Add(searchQuery.Timeout)
+	}
+
+	skip, limit := searchQuery.Skip, searchQuery.Limit
+	for len(docs) > 0 {
+		if err := ctx.Err(); err != nil {
+			yield(Hit{}, err)
+			return
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return
+		}
+
+		batch := docs[:min(iterBatchSize, len(docs))]
+		docs = docs[len(batch):]
+
+		var matched []*Document
+		i.mu.RLock()
+		for _, doc := range batch {
+			ok, err := i.isMatch(doc, searchQuery.Fields, ranges)
+			if err != nil {
+				i.mu.RUnlock()
+				yield(Hit{}, err)
+				return
+			}
+			if ok {
+				matched = append(matched, doc)
+			}
+		}
+		i.mu.RUnlock()
+
+		for _, doc := range matched {
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			score, err := doc.score(searchQuery.Fields)
+			if err != nil {
+				yield(Hit{}, err)
+				return
+			}
+			if !yield(newHit(doc, score), nil) {
+				return
+			}
+
+			limit--
+			if limit == 0 {
+				return
+			}
+		}
+	}
+}
+
+// All returns every document id and original document in id order.
+// The documents are the ones in the index when the loop starts
+func (i *Index) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		i.mu.RLock()
+		docs := sortedDocuments(i.Documents)
+		i.mu.RUnlock()
+
+		for _, doc := range docs {
+			if !yield(doc.ID, doc.Original) {
+				return
+			}
+		}
+	}
+}
+
+// sortedDocuments returns the documents sorted by id
+func sortedDocuments(documents map[string]*Document) []*Document {
+	docs := make([]*Document, 0, len(documents))
+	for _, doc := range documents {
+		docs = append(docs, doc)
+	}
+
+	slices.SortFunc(docs, func(a, b *Document) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return docs
+}
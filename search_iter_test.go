@@ -0,0 +1,152 @@
+package gofindit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIndex_SearchIter(t *testing.T) {
+	type Test struct {
+		Odd int `find:"odd"`
+	}
+
+	index := New()
+	for n := 0; n < 1000; n++ {
+		index.Index(fmt.Sprintf("%04d", n), Test{Odd: n % 2})
+	}
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+		stop  int // Break after this many hits, 0 for never
+		want  []string
+		count int
+	}{
+		{"Lazy in id order", SearchQuery{Fields: []SearchQueryField{{Field: "odd", Type: "range", Value: []int{1, 1}}}}, 3, []string{"0001", "0003", "0005"}, 3},
+		{"Lazy skip and limit", SearchQuery{Skip: 300, Limit: 2, Fields: []SearchQueryField{{Field: "odd", Type: "range", Value: []int{0, 0}}}}, 0, []string{"0600", "0602"}, 2},
+		{"No limit streams everything", SearchQuery{}, 0, []string{"0000", "0001"}, 1000},
+		{"Sorted", SearchQuery{Sorts: []SortField{{Field: "_id", Order: "desc"}}}, 2, []string{"0999", "0998"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for hit, err := range index.SearchIter(context.Background(), tt.query) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, hit.ID)
+				if len(ids) == tt.stop {
+					break
+				}
+			}
+
+			if len(ids) != tt.count {
+				t.Fatalf("expected %d hits, got %d", tt.count, len(ids))
+			}
+			for i := range tt.want {
+				if ids[i] != tt.want[i] {
+					t.Errorf("expected %s at %d, got %s", tt.want[i], i, ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_SearchIter_writes(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	// The index is not locked while the loop body runs
+	count := 0
+	for hit, err := range index.SearchIter(context.Background(), SearchQuery{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := index.Index("new-"+hit.ID, TestMember{Name: "new"}); err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+
+	if count != 4 {
+		t.Errorf("expected 4 hits, got %d", count)
+	}
+}
+
+func TestIndex_SearchIter_context(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, query := range []SearchQuery{{}, {SortBy: "name"}} {
+		var gotErr error
+		for _, err := range index.SearchIter(ctx, query) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+		}
+
+		if gotErr != context.Canceled {
+			t.Errorf("expected context canceled, got %v", gotErr)
+		}
+	}
+
+	// Query errors are yielded
+	for _, err := range index.SearchIter(context.Background(), SearchQuery{Sort: "sideways"}) {
+		if err == nil {
+			t.Errorf("expected invalid sort to error")
+		}
+	}
+}
+
+func TestIndex_SearchIter_timeout(t *testing.T) {
+	type Test struct {
+		N int `find:"n"`
+	}
+
+	index := New()
+	for n := 0; n < 1000; n++ {
+		index.Index(fmt.Sprint(n), Test{N: n})
+	}
+
+	// Timeouts stop with the hits matched so far instead of an error
+	for _, query := range []SearchQuery{{Timeout: time.Nanosecond}, {SortBy: "n", Timeout: time.Nanosecond}} {
+		count := 0
+		for _, err := range index.SearchIter(context.Background(), query) {
+			if err != nil {
+				t.Fatalf("expected no error on timeout, got %v", err)
+			}
+			count++
+		}
+
+		if count >= 1000 {
+			t.Errorf("expected partial hits on timeout, got %d", count)
+		}
+	}
+}
+
+func TestIndex_All(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	var ids []string
+	for id, doc := range index.All() {
+		if doc.(TestMember).Name != id {
+			t.Errorf("expected document %s, got %+v", id, doc)
+		}
+		ids = append(ids, id)
+	}
+
+	want := []string{"billy", "molly", "sally", "tommy"}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, ids)
+	}
+
+	// Breaking early stops the iterator
+	for range index.All() {
+		break
+	}
+}
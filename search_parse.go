@@ -53,12 +53,13 @@ func StringToSearchQuery(input string) (*SearchQuery, error) {
 		}
 	}
 
-	// Iterate over the map of parameters
-	for fieldName, values := range params {
+	// Iterate over the parameters in the order they are in the input
+	for _, fieldName := range paramNames(input) {
 		if fieldName == "limit" || fieldName == "skip" || fieldName == "sort" {
 			// Skip special parameters
 			continue
 		}
+		values := params[fieldName]
 		if len(values) == 0 {
 			continue
 		}
@@ -76,10 +77,14 @@ func StringToSearchQuery(input string) (*SearchQuery, error) {
 			}
 		}
 
-		// Map the value to the appropriate type
-		valueAny, err := stringToAny(value)
-		if err != nil {
-			return nil, err
+		// Map lists to the appropriate type, single values are left as
+		// strings since only the field knows what type it should be
+		var valueAny any = value
+		if strings.Contains(value, ",") {
+			valueAny, err = stringToAny(value)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		// If searchType is empty, check if the value is a slice
@@ -125,6 +130,23 @@ func StringToSearchQuery(input string) (*SearchQuery, error) {
 	return searchQuery, nil
 }
 
+// paramNames returns the unique parameter names in the order they are in the input
+func paramNames(input string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, param := range strings.Split(input, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(name)
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
 func JsontoSearchQueries(jsonBytes []byte) (*SearchQuery, error) {
 	var searchQuery SearchQuery
 	err := json.Unmarshal(jsonBytes, &searchQuery)
@@ -1,7 +1,10 @@
 package gofindit
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -341,3 +344,430 @@ func TestIndex_Search_offset(t *testing.T) {
 		return
 	}
 }
+
+func TestIndex_Search_existsMissing(t *testing.T) {
+	type Test struct {
+		Name  string  `find:"name"`
+		Email *string `find:"email"`
+	}
+
+	index := New()
+	email := "billy@example.com"
+	index.Index("1", Test{Name: "Billy", Email: &email})
+	index.Index("2", Test{Name: "Sally"})
+
+	tests := []struct {
+		queryType string
+		want      string
+	}{
+		{"exists", "Billy"},
+		{"missing", "Sally"},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: "email", Type: tt.queryType}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 1 || results[0].(Test).Name != tt.want {
+			t.Errorf("expected %s to only match %s, got %v", tt.queryType, tt.want, results)
+		}
+	}
+}
+
+func TestIndex_Search_multiFieldMatch(t *testing.T) {
+	type Test struct {
+		Name string `find:"name,fields=keyword|ngram|auto:text:edge_ngram"`
+	}
+
+	index := New()
+	for i, name := range []string{"Billy Smith", "Sally Smithers", "Molly Jones"} {
+		index.Index(fmt.Sprint(i), Test{Name: name})
+	}
+
+	tests := []struct {
+		field     string
+		queryType string
+		value     string
+		want      int
+	}{
+		{"name", "match", "billy smith", 1},
+		{"name", "match", "smith", 1},
+		{"name.keyword", "match", "Billy Smith", 1},
+		{"name.keyword", "match", "billy smith", 0},
+		{"name.keyword", "partial", "Smith", 2},
+		{"name.ngram", "match", "mit", 2},
+		{"name.ngram", "match", "oll", 1},
+		{"name.auto", "match", "smi", 2},
+		{"name.auto", "match", "mit", 0},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: tt.field, Type: tt.queryType, Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != tt.want {
+			t.Errorf("expected %d results for %s %s %q, got %d", tt.want, tt.field, tt.queryType, tt.value, len(results))
+		}
+	}
+}
+
+func TestIndex_Search_phonetic(t *testing.T) {
+	type Test struct {
+		Name string `find:"name,analyzer=soundex,fields=metaphone:text:double_metaphone"`
+	}
+
+	index := New()
+	for i, name := range []string{"Robert Smith", "Katherine Jones", "Molly Jones"} {
+		index.Index(fmt.Sprint(i), Test{Name: name})
+	}
+
+	tests := []struct {
+		field string
+		value string
+		want  []string
+	}{
+		{"name", "Rupert", []string{"Robert Smith"}},
+		{"name", "robert smyth", []string{"Robert Smith"}},
+		{"name", "Jonez", []string{"Katherine Jones", "Molly Jones"}},
+		{"name.metaphone", "Kathryn", []string{"Katherine Jones"}},
+		{"name.metaphone", "Mollie", []string{"Molly Jones"}},
+		{"name", "Billy", nil},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			SortBy: "_id",
+			Fields: []SearchQueryField{{Field: tt.field, Type: "match", Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, result := range results {
+			names = append(names, result.(Test).Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("expected %v for %s %q, got %v", tt.want, tt.field, tt.value, names)
+		}
+	}
+}
+
+func TestIndex_Search_multiFieldSort(t *testing.T) {
+	type Test struct {
+		Name string `find:"name,fields=keyword"`
+	}
+
+	index := New()
+	for i, name := range []string{"Sally", "Billy", "Molly"} {
+		index.Index(fmt.Sprint(i), Test{Name: name})
+	}
+
+	results, err := index.Search(SearchQuery{
+		Sort:   "asc",
+		SortBy: "name.keyword",
+		Fields: []SearchQueryField{{Field: "name.keyword", Type: "exists"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"Billy", "Molly", "Sally"}
+	for i, result := range results {
+		if result.(Test).Name != want[i] {
+			t.Errorf("expected %s at %d, got %s", want[i], i, result.(Test).Name)
+		}
+	}
+}
+
+func TestIndex_Search_rangePreEpoch(t *testing.T) {
+	type Test struct {
+		Name     string    `find:"name"`
+		Birthday time.Time `find:"birthday"`
+	}
+
+	index := New()
+	index.Index("1", Test{Name: "Billy", Birthday: time.Date(1955, 6, 1, 0, 0, 0, 0, time.UTC)})
+	index.Index("2", Test{Name: "Sally", Birthday: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)})
+	index.Index("3", Test{Name: "Molly", Birthday: time.Date(1985, 2, 1, 0, 0, 0, 0, time.UTC)})
+	index.Index("4", Test{Name: "Jimmy", Birthday: time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	tests := []struct {
+		name     string
+		min, max time.Time
+		want     int
+	}{
+		{"before epoch", time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), 2},
+		{"across epoch", time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), 2},
+		{"after epoch", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), 1},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: "birthday", Type: "range", Value: []time.Time{tt.min, tt.max}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != tt.want {
+			t.Errorf("expected %d results %s, got %d", tt.want, tt.name, len(results))
+		}
+	}
+}
+
+func TestIndex_Search_rangeDateString(t *testing.T) {
+	type Test struct {
+		Name    string `find:"name"`
+		Created string `find:"created,type=date,timezone=America/New_York,layouts=date|rfc3339"`
+	}
+
+	index := New()
+	index.Index("1", Test{Name: "Billy", Created: "2023-03-14"})
+	index.Index("2", Test{Name: "Sally", Created: "2023-03-15T02:00:00Z"}) // 2023-03-14 in New York
+	index.Index("3", Test{Name: "Molly", Created: "2023-03-16"})
+
+	results, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "created", Type: "range", Value: []string{"2023-03-13", "2023-03-14"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+}
+
+type TestJobStatus string
+
+func (s TestJobStatus) EnumValues() []string {
+	return []string{"queued", "running", "done"}
+}
+
+type TestJob struct {
+	Name    string        `find:"name"`
+	Timeout time.Duration `find:"timeout"`
+	Status  TestJobStatus `find:"status"`
+	Cost    string        `find:"cost,type=decimal"`
+}
+
+func TestIndex_Search_durationEnumDecimal(t *testing.T) {
+	index := New()
+	jobs := []TestJob{
+		{Name: "a", Timeout: 30 * time.Second, Status: "done", Cost: "10.10"},
+		{Name: "b", Timeout: 5 * time.Minute, Status: "queued", Cost: "0.30"},
+		{Name: "c", Timeout: 90 * time.Second, Status: "running", Cost: "1000000000000000000000.01"},
+	}
+	for i, job := range jobs {
+		if err := index.Index(fmt.Sprint(i), job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Enum values outside the set are not indexed
+	if err := index.Index("bad", TestJob{Status: "lost"}); err == nil {
+		t.Errorf("expected enum value outside the set to error")
+	}
+
+	tests := []struct {
+		field string
+		value any
+		sort  string
+		want  []string
+	}{
+		{"timeout", []string{"1m", "10m"}, "asc", []string{"c", "b"}},
+		{"status", []string{"queued", "running"}, "desc", []string{"c", "b"}},
+		{"cost", []string{"0.3", "10.1"}, "asc", []string{"b", "a"}},
+		{"cost", []string{"0", "1e30"}, "desc", []string{"c", "a", "b"}},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Sort:   tt.sort,
+			SortBy: tt.field,
+			Fields: []SearchQueryField{{Field: tt.field, Type: "range", Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != len(tt.want) {
+			t.Errorf("expected %d results for %s in %v, got %d", len(tt.want), tt.field, tt.value, len(results))
+			continue
+		}
+		for i, result := range results {
+			if result.(TestJob).Name != tt.want[i] {
+				t.Errorf("expected %s at %d for %s in %v, got %s", tt.want[i], i, tt.field, tt.value, result.(TestJob).Name)
+			}
+		}
+	}
+
+	// Enum query values outside the set are an error
+	_, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "status", Type: "range", Value: []string{"queued", "lost"}}},
+	})
+	if err == nil {
+		t.Errorf("expected enum query value outside the set to error")
+	}
+}
+
+func TestIndex_Search_durationEnumDecimalMatch(t *testing.T) {
+	index := New()
+	index.Index("1", TestJob{Name: "a", Timeout: 90 * time.Second, Status: "running", Cost: "10.10"})
+	index.Index("2", TestJob{Name: "b", Timeout: 5 * time.Minute, Status: "done", Cost: "0.30"})
+
+	tests := []struct {
+		field string
+		value any
+		want  string
+	}{
+		{"timeout", "1m30s", "a"},
+		{"timeout", 5 * time.Minute, "b"},
+		{"status", "done", "b"},
+		{"cost", "10.1", "a"},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: tt.field, Type: "match", Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 1 || results[0].(TestJob).Name != tt.want {
+			t.Errorf("expected %s for %s match %v, got %v", tt.want, tt.field, tt.value, results)
+		}
+	}
+
+	// Match values are checked against the enum set and parsed as durations
+	for field, value := range map[string]any{"status": "lost", "timeout": "soon"} {
+		_, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: field, Type: "match", Value: value}},
+		})
+		if err == nil {
+			t.Errorf("expected %s match %v to error", field, value)
+		}
+	}
+}
+
+func TestIndex_Search_rangeNegative(t *testing.T) {
+	type Test struct {
+		Name  string  `find:"name"`
+		Temp  int8    `find:"temp"`
+		Delta float64 `find:"delta"`
+	}
+
+	index := New()
+	index.Index("1", Test{Name: "Billy", Temp: -20, Delta: -1.5})
+	index.Index("2", Test{Name: "Sally", Temp: 5, Delta: 0.5})
+	index.Index("3", Test{Name: "Molly", Temp: -3, Delta: -0.25})
+
+	tests := []struct {
+		field string
+		value any
+		want  []string
+	}{
+		{"temp", []int{-10, 10}, []string{"Molly", "Sally"}},
+		{"temp", []int64{-30, -1}, []string{"Billy", "Molly"}},
+		{"delta", []float64{-2, 0}, []string{"Billy", "Molly"}},
+		{"delta", []int{-1, 1}, []string{"Molly", "Sally"}},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			SortBy: tt.field,
+			Fields: []SearchQueryField{{Field: tt.field, Type: "range", Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != len(tt.want) {
+			t.Errorf("expected %d results for %s in %v, got %d", len(tt.want), tt.field, tt.value, len(results))
+			continue
+		}
+		for i, result := range results {
+			if result.(Test).Name != tt.want[i] {
+				t.Errorf("expected %s at %d for %s in %v, got %s", tt.want[i], i, tt.field, tt.value, result.(Test).Name)
+			}
+		}
+	}
+
+	// Equal numbers of different kinds match
+	results, err := index.Search(SearchQuery{
+		Fields: []SearchQueryField{{Field: "temp", Type: "match", Value: int64(-3)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result for temp -3, got %d", len(results))
+	}
+}
+
+func TestIndex_SearchContext(t *testing.T) {
+	type Test struct {
+		N int `find:"n"`
+	}
+
+	index := New()
+	for n := 0; n < 1000; n++ {
+		index.Index(fmt.Sprint(n), Test{N: n})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := index.SearchContext(ctx, SearchQuery{}); err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if _, err := index.SearchHitsContext(ctx, SearchQuery{}); err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if _, err := index.AggregateContext(ctx, SearchQuery{}, nil); err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if _, err := index.HybridContext(ctx, HybridQuery{Queries: []HybridSubQuery{{}}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+
+	// Timeouts return what was matched so far
+	response, err := index.SearchHits(SearchQuery{Limit: 1000, Timeout: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.TimedOut {
+		t.Errorf("expected search to time out")
+	}
+	if response.Total >= 1000 || len(response.Hits) != response.Total {
+		t.Errorf("expected partial results, got %d hits of %d", len(response.Hits), response.Total)
+	}
+
+	response, err = index.SearchHits(SearchQuery{Limit: 1000, Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.TimedOut || response.Total != 1000 {
+		t.Errorf("expected all 1000 results without timing out, got %d timed out %v", response.Total, response.TimedOut)
+	}
+
+	if _, err := index.Search(SearchQuery{Limit: 1000, Timeout: time.Nanosecond}); err != ErrTimedOut {
+		t.Errorf("expected search to time out, got %v", err)
+	}
+	if _, err := index.Aggregate(SearchQuery{Timeout: time.Nanosecond}, nil); err != ErrTimedOut {
+		t.Errorf("expected aggregate to time out, got %v", err)
+	}
+	if _, err := index.Search(SearchQuery{Timeout: -time.Second}); err == nil {
+		t.Errorf("expected negative timeout to error")
+	}
+}
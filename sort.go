@@ -0,0 +1,246 @@
+package gofindit
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"sort"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+// SortField sorts search results by a field, the distance of a geo
+// field from a point or the special _score and _id fields
+type SortField struct {
+	Field   string `json:"field"`   // Field name, "_score" or "_id"
+	Order   string `json:"order"`   // asc or desc, defaults to asc or desc for _score
+	Missing string `json:"missing"` // first or last, defaults to last
+
+	// Mode picks the value of fields with many values, like pets.age
+	// from an array of pets. min, max or avg, defaults to min for asc
+	// and max for desc
+	Mode string `json:"mode"`
+
+	// Geo sorts a geo field by distance from this point
+	Geo *fields.GeoPoint `json:"geo"`
+}
+
+func (sf *SortField) Sanatize() {
+	if sf.Order == "" {
+		sf.Order = "asc"
+		if sf.Field == "_score" {
+			sf.Order = "desc"
+		}
+	}
+
+	if sf.Missing == "" {
+		sf.Missing = "last"
+	}
+
+	if sf.Mode == "" {
+		sf.Mode = "min"
+		if sf.Order == "desc" {
+			sf.Mode = "max"
+		}
+	}
+}
+
+func (sf *SortField) Validate() error {
+	if sf.Field == "" {
+		return fmt.Errorf("sort field cannot be empty")
+	}
+
+	if sf.Order != "" && sf.Order != "asc" && sf.Order != "desc" {
+		return fmt.Errorf("invalid sort order %s", sf.Order)
+	}
+
+	if sf.Missing != "" && sf.Missing != "first" && sf.Missing != "last" {
+		return fmt.Errorf("invalid sort missing %s", sf.Missing)
+	}
+
+	switch sf.Mode {
+	case "", "min", "max", "avg":
+	default:
+		return fmt.Errorf("invalid sort mode %s", sf.Mode)
+	}
+
+	if sf.Geo != nil && (sf.Field == "_score" || sf.Field == "_id") {
+		return fmt.Errorf("cannot sort %s by geo distance", sf.Field)
+	}
+
+	return nil
+}
+
+// sorts returns the sort list of the query with SortBy first
+func (sq *SearchQuery) sorts() []SortField {
+	var sorts []SortField
+	if sq.SortBy != "" {
+		sorts = append(sorts, SortField{Field: sq.SortBy, Order: sq.Sort, Geo: sq.SortGeo})
+	}
+	sorts = append(sorts, sq.Sorts...)
+
+	for i := range sorts {
+		sorts[i].Sanatize()
+	}
+
+	return sorts
+}
+
+// sortValue is the value a hit is sorted by for a single sort field
+type sortValue struct {
+	key     []byte
+	num     float64
+	numeric bool // Compare num instead of key
+	ok      bool // False if the hit is missing the field
+}
+
+// compare returns -1, 0 or 1 comparing the values, missing values are
+// always first or last no matter the order
+func (sv sortValue) compare(other sortValue, sf SortField) int {
+	if !sv.ok || !other.ok {
+		if sv.ok == other.ok {
+			return 0
+		}
+		if sv.ok == (sf.Missing == "last") {
+			return -1
+		}
+		return 1
+	}
+
+	var c int
+	if sv.numeric {
+		c = cmp.Compare(sv.num, other.num)
+	} else {
+		c = bytes.Compare(sv.key, other.key)
+	}
+
+	if sf.Order == "desc" {
+		return -c
+	}
+	return c
+}
+
+// sortHits sorts the hits by each sort field in order and sets their
+// sort values. The _score of a hit is its score and _id is its id. Hits
+// are always sorted by _id last so results are the same for every page.
+// The sort fields used, with the _id, are returned
+func (i *Index) sortHits(hits []hit, sorts []SortField) ([]SortField, error) {
+	if len(sorts) == 0 {
+		sorts = []SortField{{Field: "_score"}}
+		sorts[0].Sanatize()
+	}
+	if sorts[len(sorts)-1].Field != "_id" {
+		sorts = append(sorts, SortField{Field: "_id"})
+		sorts[len(sorts)-1].Sanatize()
+	}
+
+	docs := make([]*Document, len(hits))
+	for n, hit := range hits {
+		docs[n] = hit.doc
+		hits[n].sort = make([]sortValue, len(sorts))
+	}
+
+	// Work out every value up front so sorting only compares
+	for s, sf := range sorts {
+		getValue, err := i.sortValueFunc(sf, docs)
+		if err != nil {
+			return nil, err
+		}
+
+		for n := range hits {
+			hits[n].sort[s] = getValue(hits[n])
+		}
+	}
+
+	sort.SliceStable(hits, func(a, b int) bool {
+		return compareSortValues(hits[a].sort, hits[b].sort, sorts) < 0
+	})
+
+	return sorts, nil
+}
+
+// compareSortValues returns -1, 0 or 1 comparing the sort values of two hits
+func compareSortValues(a, b []sortValue, sorts []SortField) int {
+	for s, sf := range sorts {
+		if c := a[s].compare(b[s], sf); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// sortValueFunc returns a func that gets the value of a hit for the sort field
+func (i *Index) sortValueFunc(sf SortField, docs []*Document) (func(hit) sortValue, error) {
+	switch {
+	case sf.Field == "_score":
+		return func(h hit) sortValue {
+			return sortValue{num: h.score, numeric: true, ok: true}
+		}, nil
+	case sf.Field == "_id":
+		return func(h hit) sortValue {
+			return sortValue{key: []byte(h.doc.ID), ok: true}
+		}, nil
+	case sf.Geo != nil:
+		return func(h hit) sortValue {
+			var distances []float64
+			for _, field := range docFields(h.doc, sf.Field) {
+				if geo, ok := field.(*fields.Geo); ok {
+					distances = append(distances, geo.DistanceTo(*sf.Geo))
+				}
+			}
+			return numSortValue(distances, sf.Mode)
+		}, nil
+	}
+
+	dv := i.columnFor(sf.Field, docs)
+	if dv == nil {
+		return func(h hit) sortValue { return sortValue{} }, nil
+	}
+	if sf.Mode == "avg" && !dv.numeric() {
+		return nil, fmt.Errorf("cannot use sort mode avg on %s field %s", dv.typ, sf.Field)
+	}
+
+	return func(h hit) sortValue {
+		values := dv.get(h.doc.num)
+		if sf.Mode == "avg" {
+			nums := make([]float64, len(values))
+			for n, value := range values {
+				nums[n] = value.num
+			}
+			return numSortValue(nums, sf.Mode)
+		}
+
+		var sv sortValue
+		for _, value := range values {
+			c := bytes.Compare(value.key, sv.key)
+			if !sv.ok || (sf.Mode == "min" && c < 0) || (sf.Mode == "max" && c > 0) {
+				sv = sortValue{key: value.key, ok: true}
+			}
+		}
+		return sv
+	}, nil
+}
+
+// numSortValue picks the min, max or avg of the numbers
+func numSortValue(nums []float64, mode string) sortValue {
+	if len(nums) == 0 {
+		return sortValue{}
+	}
+
+	sv := sortValue{num: nums[0], numeric: true, ok: true}
+	for _, num := range nums[1:] {
+		switch mode {
+		case "min":
+			sv.num = min(sv.num, num)
+		case "max":
+			sv.num = max(sv.num, num)
+		case "avg":
+			sv.num += num
+		}
+	}
+	if mode == "avg" {
+		sv.num /= float64(len(nums))
+	}
+
+	return sv
+}
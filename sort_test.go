@@ -0,0 +1,128 @@
+package gofindit
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+type TestMember struct {
+	Name  string          `find:"name,type=keyword"`
+	Team  string          `find:"team,type=keyword"`
+	Level *int            `find:"level"`
+	Pets  []TestMemberPet `find:"pets"`
+	Tags  []string        `find:"tags"`
+}
+
+type TestMemberPet struct {
+	Name string `find:"name"`
+	Age  int    `find:"age"`
+}
+
+func newTestMemberIndex(t *testing.T) *Index {
+	t.Helper()
+
+	one, two := 1, 2
+	return newTestIndex(t, New(), map[string]any{
+		"billy": TestMember{Name: "billy", Team: "red", Level: &two, Pets: []TestMemberPet{{Name: "a", Age: 2}, {Name: "b", Age: 12}}, Tags: []string{"x", "c"}},
+		"sally": TestMember{Name: "sally", Team: "blue", Level: &one, Pets: []TestMemberPet{{Name: "c", Age: 5}, {Name: "d", Age: 6}}, Tags: []string{"b", "c"}},
+		"molly": TestMember{Name: "molly", Team: "red", Pets: []TestMemberPet{{Name: "e", Age: 9}}, Tags: []string{"z", "a"}},
+		"tommy": TestMember{Name: "tommy", Team: "blue", Level: &two},
+	})
+}
+
+func TestIndex_Search_sorts(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	tests := []struct {
+		name  string
+		sorts []SortField
+		want  []string
+	}{
+		{"Id by default", nil, []string{"billy", "molly", "sally", "tommy"}},
+		{"Multiple keys", []SortField{{Field: "team"}, {Field: "name", Order: "desc"}}, []string{"tommy", "sally", "molly", "billy"}},
+		{"Missing last", []SortField{{Field: "level"}}, []string{"sally", "billy", "tommy", "molly"}},
+		{"Missing first", []SortField{{Field: "level", Order: "desc", Missing: "first"}}, []string{"molly", "billy", "tommy", "sally"}},
+		{"Array min", []SortField{{Field: "pets.age"}}, []string{"billy", "sally", "molly", "tommy"}},
+		{"Array max", []SortField{{Field: "pets.age", Order: "desc"}}, []string{"billy", "molly", "sally", "tommy"}},
+		{"Array avg", []SortField{{Field: "pets.age", Mode: "avg"}}, []string{"sally", "billy", "molly", "tommy"}},
+		{"Slice min", []SortField{{Field: "tags", Mode: "min"}}, []string{"molly", "sally", "billy", "tommy"}},
+		{"Slice max", []SortField{{Field: "tags", Order: "desc", Mode: "max"}}, []string{"molly", "billy", "sally", "tommy"}},
+		{"Id tie breaker", []SortField{{Field: "_score"}, {Field: "_id", Order: "desc"}}, []string{"tommy", "sally", "molly", "billy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := index.Search(SearchQuery{Sorts: tt.sorts})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != len(tt.want) {
+				t.Fatalf("expected %d results, got %d", len(tt.want), len(results))
+			}
+			for i, result := range results {
+				if result.(TestMember).Name != tt.want[i] {
+					t.Errorf("expected %s at %d, got %s", tt.want[i], i, result.(TestMember).Name)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_Search_sortsPaging(t *testing.T) {
+	index := newTestMemberIndex(t)
+
+	// Ties on team are broken by id so pages never overlap
+	var names []string
+	for skip := uint(0); skip < 4; skip += 2 {
+		results, err := index.Search(SearchQuery{
+			Limit:  2,
+			Skip:   skip,
+			SortBy: "team",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, result := range results {
+			names = append(names, result.(TestMember).Name)
+		}
+	}
+
+	want := []string{"sally", "tommy", "billy", "molly"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected %s at %d, got %s", want[i], i, names[i])
+		}
+	}
+}
+
+func TestSortField_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    SortField
+		wantErr bool
+	}{
+		{"Valid", SortField{Field: "name", Order: "desc", Missing: "first", Mode: "avg"}, false},
+		{"Empty field", SortField{}, true},
+		{"Invalid order", SortField{Field: "name", Order: "up"}, true},
+		{"Invalid missing", SortField{Field: "name", Missing: "middle"}, true},
+		{"Invalid mode", SortField{Field: "name", Mode: "median"}, true},
+		{"Geo score", SortField{Field: "_score", Geo: &fields.GeoPoint{}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sort.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Avg needs numbers
+	_, err := newTestMemberIndex(t).Search(SearchQuery{Sorts: []SortField{{Field: "name", Mode: "avg"}}})
+	if err == nil {
+		t.Errorf("expected avg sort on a keyword field to error")
+	}
+}
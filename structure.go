@@ -2,31 +2,129 @@ package gofindit
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/brianvoe/gofindit/fields"
 )
 
+// structure is the flattened fields of a document
+// along with the names of the fields that were nil
+type structure struct {
+	fields  map[string]fields.Field
+	nulls   map[string]bool
+	options map[string]tagOptions
+
+	// depths is how many embedded structs deep each name was found
+	// in the struct it belongs to, so shallower fields can win
+	depths map[string]int
+}
+
+func makeStructure() *structure {
+	return &structure{
+		fields:  make(map[string]fields.Field),
+		nulls:   make(map[string]bool),
+		options: make(map[string]tagOptions),
+		depths:  make(map[string]int),
+	}
+}
+
 func getStructure(v any, parent string) (map[string]fields.Field, error) {
-	if reflect.TypeOf(v).Kind() != reflect.Struct {
+	s, err := newStructure(v, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fields, nil
+}
+
+// newStructure walks the struct, or pointer to a struct,
+// and returns its flattened fields and nil fields
+func newStructure(v any, parent string) (*structure, error) {
+	val, ok := indirect(reflect.ValueOf(v))
+	if !ok {
+		return nil, fmt.Errorf("v is nil")
+	}
+	if val.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("v is not a struct")
 	}
 
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Ptr {
+	s := makeStructure()
+	err := s.walk(val, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// indirect follows pointers and interfaces to the value
+// they hold and returns false if it reaches a nil
+func indirect(val reflect.Value) (reflect.Value, bool) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return val, false
+		}
 		val = val.Elem()
 	}
 
-	fieldsFinal := make(map[string]fields.Field)
+	return val, val.IsValid()
+}
+
+func (s *structure) walk(val reflect.Value, parent string) error {
+	// Copy into an addressable value so methods
+	// with pointer receivers can be used by fields
+	if !val.CanAddr() {
+		addressable := reflect.New(val.Type()).Elem()
+		addressable.Set(val)
+		val = addressable
+	}
+
+	tags, err := typeTagOptions(val.Type())
+	if err != nil {
+		return err
+	}
+
+	// Embedded structs are walked on their own and promoted
+	// at the end so the fields of this struct can shadow them
+	var embeddedStructs []*structure
 
 	for i := 0; i < val.NumField(); i++ {
 		valueField := val.Field(i)
 		typeField := val.Type().Field(i)
-		name, _ := typeField.Tag.Lookup("find")
+		options := tags[i]
+		name := options.Name
 		if name == "-" {
 			continue
 		}
+		_, tagged := typeField.Tag.Lookup("find")
+
+		// Untagged embedded structs have their fields promoted into
+		// the parent the same as Go does. Unexported embedded structs
+		// cannot be read so they are skipped like any unexported field
+		if typeField.Anonymous && !tagged && typeField.IsExported() {
+			embedded, ok := indirect(valueField)
+			if !ok {
+				continue
+			}
+			if embedded.Kind() == reflect.Struct && embedded.Type() != reflect.TypeOf(time.Time{}) {
+				child := makeStructure()
+				err := child.walk(embedded, parent)
+				if err != nil {
+					return err
+				}
+				embeddedStructs = append(embeddedStructs, child)
+				continue
+			}
+		}
+
+		// Unexported fields cannot be read so they are always skipped
+		if !typeField.IsExported() {
+			continue
+		}
 
 		if name == "" {
 			name = typeField.Name
@@ -35,93 +133,506 @@ func getStructure(v any, parent string) (map[string]fields.Field, error) {
 		if parent != "" {
 			name = parent + "." + name
 		}
+		s.depths[name] = 0
+
+		// Values that are not indexed are still in the original document
+		if !options.Index {
+			continue
+		}
+		s.options[name] = options
+
+		// Pointers and interfaces are resolved to the value
+		// they hold and nils are tracked for missing queries
+		valueField, ok := indirect(valueField)
+		if !ok {
+			s.nulls[name] = true
+			continue
+		}
+
+		// Types can control their indexed value through Findable,
+		// driver.Valuer, encoding.TextMarshaler or fmt.Stringer
+		marshaled, ok, err := marshalValue(valueField)
+		if err != nil {
+			return fmt.Errorf("failed to marshal field %s: %v", name, err)
+		}
+		if ok {
+			valueField, ok = indirect(reflect.ValueOf(marshaled))
+			if !ok {
+				s.nulls[name] = true
+				continue
+			}
+		}
+
+		fieldTag := options.Type
 
-		fieldTag := typeField.Tag.Get("field")
+		// Multi fields index the same value again under name.sub
+		for _, sub := range options.Fields {
+			subField, err := getSubField(valueField, sub)
+			if err != nil {
+				return fmt.Errorf("failed to create multi field %s.%s: %v", name, sub.Name, err)
+			}
+			s.fields[name+"."+sub.Name] = subField
+			s.options[name+"."+sub.Name] = sub
+		}
+
+		// Geo points can be a [2]float64 or []float64 tagged as geo
+		// or a struct with lat and lon fields
+		if fieldTag == fields.DefaultGeo || isGeoStruct(valueField.Type()) {
+			geoField, err := getGeoField(valueField, options.Config)
+			if err != nil {
+				return err
+			}
+			s.fields[name] = geoField
+			continue
+		}
+
+		// IPs are a netip.Addr, net.IP or a string tagged as ip
+		if fieldTag == fields.DefaultIP || isIPType(valueField.Type()) {
+			// Documents without an address are skipped
+			if valueField.IsZero() || (valueField.Kind() == reflect.Slice && valueField.Len() == 0) {
+				s.nulls[name] = true
+				continue
+			}
+
+			ipField, err := getIPField(valueField, options.Config)
+			if err != nil {
+				return err
+			}
+			s.fields[name] = ipField
+			continue
+		}
+
+		// Vectors are a []float32 or a []float64 tagged as vector
+		if fieldTag == fields.DefaultVector || valueField.Type() == reflect.TypeOf([]float32(nil)) {
+			// Documents without an embedding are skipped
+			if valueField.Len() == 0 {
+				s.nulls[name] = true
+				continue
+			}
+
+			vectorField, err := getVectorField(valueField, options.Config)
+			if err != nil {
+				return err
+			}
+			s.fields[name] = vectorField
+			continue
+		}
 
 		switch valueField.Kind() {
 		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 			reflect.Float32, reflect.Float64, reflect.Bool:
 			// Simplified handling for basic types
-			basicField, err := getBasicField(valueField, fieldTag)
+			basicField, err := getBasicField(valueField, fieldTag, options.Config)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			fieldsFinal[name] = basicField
+			s.fields[name] = basicField
 		case reflect.Array, reflect.Slice:
+			if valueField.Kind() == reflect.Slice && valueField.IsNil() {
+				s.nulls[name] = true
+				continue
+			}
+
 			elemType := valueField.Type().Elem()
+			for elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+
 			if elemType.Kind() == reflect.Struct {
 				// Handle slice of structs
 				for j := 0; j < valueField.Len(); j++ {
-					elemValue := valueField.Index(j)
-					structFields, err := getStructure(elemValue.Interface(), fmt.Sprintf("%s[%d]", name, j))
-					if err != nil {
-						return nil, err
+					elemName := fmt.Sprintf("%s[%d]", name, j)
+					elemValue, ok := indirect(valueField.Index(j))
+					if !ok {
+						s.nulls[elemName] = true
+						continue
 					}
-					for k, v := range structFields {
-						fieldsFinal[k] = v
+
+					err := s.walk(elemValue, elemName)
+					if err != nil {
+						return err
 					}
 				}
 			} else {
 				// Simplified handling for slices of basic types
-				basicField, err := getBasicField(valueField, fieldTag)
+				basicField, err := getBasicField(valueField, fieldTag, options.Config)
 				if err != nil {
-					return nil, err
+					return err
 				}
-				fieldsFinal[name] = basicField
+				s.fields[name] = basicField
 			}
 		case reflect.Struct:
+			// Times are a basic date field
+			if valueField.Type() == reflect.TypeOf(time.Time{}) {
+				basicField, err := getBasicField(valueField, fieldTag, options.Config)
+				if err != nil {
+					return err
+				}
+				s.fields[name] = basicField
+				continue
+			}
+
 			// Recursive call for nested structs
-			structFields, err := getStructure(valueField.Interface(), name)
+			err := s.walk(valueField, name)
 			if err != nil {
-				return nil, err
+				return err
+			}
+		}
+	}
+
+	s.promote(embeddedStructs, parent)
+
+	return nil
+}
+
+// promote adds the fields of embedded structs the same way Go promotes
+// them. The shallowest field with a name wins and names found at the
+// same depth in more than one embedded struct conflict and are dropped
+func (s *structure) promote(embedded []*structure, parent string) {
+	// Find the shallowest depth of each name and how many have it
+	depths := make(map[string]int)
+	counts := make(map[string]int)
+	for _, child := range embedded {
+		for name, depth := range child.depths {
+			if topName(parent, name) != name {
+				continue
+			}
+
+			best, ok := depths[name]
+			switch {
+			case !ok || depth+1 < best:
+				depths[name] = depth + 1
+				counts[name] = 1
+			case depth+1 == best:
+				counts[name]++
+			}
+		}
+	}
+
+	// Fields of the struct itself are not shadowed
+	winners := make(map[string]*structure)
+	for name, depth := range depths {
+		if _, ok := s.depths[name]; ok || counts[name] > 1 {
+			continue
+		}
+		for _, child := range embedded {
+			if childDepth, ok := child.depths[name]; ok && childDepth+1 == depth {
+				winners[name] = child
+			}
+		}
+	}
+
+	for _, child := range embedded {
+		won := func(key string) bool { return winners[topName(parent, key)] == child }
+		for key, field := range child.fields {
+			if won(key) {
+				s.fields[key] = field
+			}
+		}
+		for key := range child.nulls {
+			if won(key) {
+				s.nulls[key] = true
+			}
+		}
+		for key, options := range child.options {
+			if won(key) {
+				s.options[key] = options
 			}
-			for k, v := range structFields {
-				fieldsFinal[k] = v
+		}
+		for key, depth := range child.depths {
+			if won(key) {
+				s.depths[key] = depth
 			}
 		}
 	}
+	for name := range winners {
+		s.depths[name] = depths[name]
+	}
+}
+
+// topName returns the name of the field directly under
+// the parent that the key, like parent.name.keyword, is in
+func topName(parent string, key string) string {
+	rest := key
+	if parent != "" {
+		rest = strings.TrimPrefix(key, parent+".")
+	}
+	if n := strings.IndexAny(rest, ".["); n >= 0 {
+		rest = rest[:n]
+	}
 
-	return fieldsFinal, nil
+	if parent == "" {
+		return rest
+	}
+	return parent + "." + rest
 }
 
 // getBasicField handles the creation of fields based on basic types.
-func getBasicField(valueField reflect.Value, fieldTag string) (fields.Field, error) {
+func getBasicField(valueField reflect.Value, fieldTag string, config map[string]any) (fields.Field, error) {
+	// Enum types declare their values
+	if enum, ok := valueField.Interface().(Enum); ok && (fieldTag == "" || fieldTag == fields.DefaultEnum) {
+		fieldTag = fields.DefaultEnum
+		config = mergeConfig(config, map[string]any{"values": enum.EnumValues()})
+	}
+
 	// Determine the field type and create the appropriate Field
 	switch valueField.Kind() {
 	// String
 	case reflect.String:
 		if fieldTag == "" {
-			fieldTag = fields.TextType
+			fieldTag = fields.DefaultText
 		}
 
 	// Number
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64:
+		if fieldTag == "" && valueField.Type() == reflect.TypeOf(time.Duration(0)) {
+			fieldTag = fields.DefaultDuration
+		}
 		if fieldTag == "" {
-			fieldTag = fields.NumberType
+			fieldTag = fields.DefaultNumber
 		}
 
 	// Bool
 	case reflect.Bool:
 		if fieldTag == "" {
-			fieldTag = fields.BooleanType
+			fieldTag = fields.DefaultBoolean
 		}
 
 	// Special handling for time.Time
 	case reflect.Struct:
 		if valueField.Type() == reflect.TypeOf(time.Time{}) {
 			if fieldTag == "" {
-				fieldTag = fields.DateType
+				fieldTag = fields.DefaultDate
 			}
 		} else {
 			return nil, fmt.Errorf("struct type not supported directly, consider using nested struct handling")
 		}
 
+	// Slices of strings are a multi value text field
+	case reflect.Array, reflect.Slice:
+		if valueField.Type().Elem().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported type: %v", valueField.Type())
+		}
+		if fieldTag == "" {
+			fieldTag = fields.DefaultText
+		}
+
+		strs := make([]string, valueField.Len())
+		for i := range strs {
+			strs[i] = valueField.Index(i).String()
+		}
+		return newBasicField(fieldTag, config, strs)
+
 	default:
 		return nil, fmt.Errorf("unsupported type: %v", valueField.Type())
 	}
 
-	return fields.GetField(fieldTag, nil)
+	return newBasicField(fieldTag, config, basicValue(valueField))
+}
+
+// newBasicField gets the field from the store and processes the value
+func newBasicField(fieldTag string, config map[string]any, value any) (fields.Field, error) {
+	basicField, err := fields.GetField(fieldTag, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = basicField.Process(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return basicField, nil
+}
+
+// mergeConfig returns a new config with the values
+// added, leaving the shared tag config untouched
+func mergeConfig(config map[string]any, values map[string]any) map[string]any {
+	merged := make(map[string]any, len(config)+len(values))
+	for k, v := range config {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// basicTypes are the builtin types for each basic kind
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// basicValue returns the value converted from a named
+// type, like type Name string, to its builtin type
+func basicValue(valueField reflect.Value) any {
+	if basicType, ok := basicTypes[valueField.Kind()]; ok && valueField.Type() != basicType {
+		return valueField.Convert(basicType).Interface()
+	}
+
+	return valueField.Interface()
+}
+
+// getSubField creates a multi field from the value
+func getSubField(valueField reflect.Value, sub tagOptions) (fields.Field, error) {
+	subField, err := fields.GetField(sub.Type, sub.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = subField.Process(basicValue(valueField))
+	if err != nil {
+		return nil, err
+	}
+
+	return subField, nil
+}
+
+// isGeoStruct checks if the type is a fields.GeoPoint or
+// a struct with float fields tagged as lat and lon
+func isGeoStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if t == reflect.TypeOf(fields.GeoPoint{}) {
+		return true
+	}
+
+	_, _, ok := geoStructIndexes(t)
+	return ok
+}
+
+// geoStructIndexes returns the field indexes of the lat and lon fields
+func geoStructIndexes(t reflect.Type) (int, int, bool) {
+	lat, lon := -1, -1
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Float64 && field.Type.Kind() != reflect.Float32 {
+			continue
+		}
+
+		switch tagName(field.Tag.Get("find")) {
+		case "lat", "latitude":
+			lat = i
+		case "lon", "lng", "longitude":
+			lon = i
+		}
+	}
+
+	return lat, lon, lat >= 0 && lon >= 0
+}
+
+// getGeoField creates a geo field from a geo struct or a lat, lon array
+func getGeoField(valueField reflect.Value, config map[string]any) (fields.Field, error) {
+	var point any
+
+	switch valueField.Kind() {
+	case reflect.Struct:
+		if valueField.Type() == reflect.TypeOf(fields.GeoPoint{}) {
+			point = valueField.Interface()
+			break
+		}
+
+		lat, lon, ok := geoStructIndexes(valueField.Type())
+		if !ok {
+			return nil, fmt.Errorf("geo struct requires lat and lon fields")
+		}
+		point = fields.GeoPoint{Lat: valueField.Field(lat).Float(), Lon: valueField.Field(lon).Float()}
+	case reflect.Array, reflect.Slice:
+		if valueField.Len() != 2 || valueField.Type().Elem().Kind() != reflect.Float64 {
+			return nil, fmt.Errorf("geo field requires 2 float64 values for lat and lon")
+		}
+		point = [2]float64{valueField.Index(0).Float(), valueField.Index(1).Float()}
+	default:
+		return nil, fmt.Errorf("unsupported type for geo field: %v", valueField.Type())
+	}
+
+	geoField, err := fields.GetField(fields.DefaultGeo, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = geoField.Process(point)
+	if err != nil {
+		return nil, err
+	}
+
+	return geoField, nil
+}
+
+// getVectorField creates a vector field from a float slice
+func getVectorField(valueField reflect.Value, config map[string]any) (fields.Field, error) {
+	if valueField.Kind() != reflect.Slice && valueField.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unsupported type for vector field: %v", valueField.Type())
+	}
+
+	vector := make([]float32, valueField.Len())
+	switch valueField.Type().Elem().Kind() {
+	case reflect.Float32, reflect.Float64:
+		for i := range vector {
+			vector[i] = float32(valueField.Index(i).Float())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type for vector field: %v", valueField.Type())
+	}
+
+	vectorField, err := fields.GetField(fields.DefaultVector, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = vectorField.Process(vector)
+	if err != nil {
+		return nil, err
+	}
+
+	return vectorField, nil
+}
+
+// isIPType checks if the type is a netip.Addr or net.IP
+func isIPType(t reflect.Type) bool {
+	return t == reflect.TypeOf(netip.Addr{}) || t == reflect.TypeOf(net.IP(nil))
+}
+
+// getIPField creates an ip field from a netip.Addr, net.IP or string
+func getIPField(valueField reflect.Value, config map[string]any) (fields.Field, error) {
+	var addr any
+
+	switch {
+	case isIPType(valueField.Type()):
+		addr = valueField.Interface()
+	case valueField.Kind() == reflect.String:
+		addr = valueField.String()
+	default:
+		return nil, fmt.Errorf("unsupported type for ip field: %v", valueField.Type())
+	}
+
+	ipField, err := fields.GetField(fields.DefaultIP, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ipField.Process(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return ipField, nil
 }
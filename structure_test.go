@@ -2,10 +2,19 @@ package gofindit
 
 import (
 	"testing"
+	"time"
 )
 
 func TestGetStructurePerson(t *testing.T) {
-	_, doc := generateDoc()
+	doc := TestData{
+		Name:      "Billy Smith",
+		Age:       30,
+		Hobbies:   []string{"hiking", "chess"},
+		Pets:      []TestPet{{Name: "Spot", Age: 3, Type: "dog", Breed: "Beagle", Toys: []string{"ball"}}},
+		Bio:       "Lorem ipsum",
+		isStudent: false,
+		Birthday:  time.Now().AddDate(-30, 0, 0),
+	}
 
 	// Get structure of the person
 	structure, err := getStructure(doc, "")
@@ -33,15 +42,18 @@ func TestGetStructurePerson(t *testing.T) {
 		// 	Toys  []string `find:"toys"`
 		// }
 
-		"name":         true,
-		"age":          true,
-		"hobbies":      true,
-		"pets":         true,
-		"bio":          true,
-		"isStudent":    true,
-		"birthday":     true,
-		"pets[0].name": true,
-		"pets[0].age":  true,
+		// isStudent is unexported so it is skipped
+		// and pets are indexed by their fields
+		"name":          true,
+		"age":           true,
+		"hobbies":       true,
+		"bio":           true,
+		"birthday":      true,
+		"pets[0].name":  true,
+		"pets[0].age":   true,
+		"pets[0].type":  true,
+		"pets[0].breed": true,
+		"pets[0].toys":  true,
 	}
 
 	// Check if all expected fields are found
@@ -56,3 +68,171 @@ func TestGetStructurePerson(t *testing.T) {
 		t.Errorf("Expected %d fields, found %d", len(expectedFields), len(structure))
 	}
 }
+
+type TestAudit struct {
+	CreatedBy string `find:"created_by"`
+}
+
+type testTimestamps struct {
+	Created time.Time `find:"created"`
+}
+
+type TestAccount struct {
+	TestAudit
+	testTimestamps
+	*TestOwner
+
+	Name     *string      `find:"name"`
+	Nickname *string      `find:"nickname"`
+	Extra    any          `find:"extra"`
+	Empty    any          `find:"empty"`
+	Tags     []string     `find:"tags"`
+	Parent   *TestAccount `find:"parent"`
+	secret   string
+}
+
+type TestOwner struct {
+	Owner string `find:"owner"`
+}
+
+func TestGetStructure_pointersEmbeddedInterfaces(t *testing.T) {
+	name := "Billy"
+	account := &TestAccount{
+		TestAudit:      TestAudit{CreatedBy: "admin"},
+		testTimestamps: testTimestamps{Created: time.Now()},
+		Name:           &name,
+		Extra:          42,
+		Parent:         &TestAccount{Name: &name},
+		secret:         "hidden",
+	}
+
+	s, err := newStructure(account, "")
+	if err != nil {
+		t.Fatalf("Failed to get structure: %v", err)
+	}
+
+	// Embedded structs are flattened, pointers and interfaces are resolved
+	for _, field := range []string{"created_by", "name", "extra", "parent.name"} {
+		if _, found := s.fields[field]; !found {
+			t.Errorf("Field %v not found in structure", field)
+		}
+	}
+
+	// Nil pointers, interfaces and slices are tracked as nulls
+	for _, field := range []string{"nickname", "empty", "tags", "parent.nickname"} {
+		if !s.nulls[field] {
+			t.Errorf("Field %v expected to be null", field)
+		}
+	}
+
+	// Unexported fields, unexported embedded structs
+	// and nil embedded structs are skipped
+	for _, field := range []string{"secret", "created", "owner", "TestAudit.created_by"} {
+		if _, found := s.fields[field]; found {
+			t.Errorf("Field %v expected to be skipped", field)
+		}
+	}
+}
+
+type TestPromotedName struct {
+	Name string `find:"name"`
+	City string `find:"city"`
+}
+
+type TestPromotedDeep struct {
+	TestPromotedName
+}
+
+type TestPromotedOther struct {
+	City string `find:"city"`
+	Zip  string `find:"zip"`
+}
+
+func TestGetStructure_embeddedShadowing(t *testing.T) {
+	// The outer field wins no matter which is declared first
+	type nameFirst struct {
+		Name string `find:"name"`
+		TestPromotedName
+	}
+	type embeddedFirst struct {
+		TestPromotedName
+		Name string `find:"name"`
+	}
+	// The shallower embedded field wins and fields at
+	// the same depth in two embedded structs conflict
+	type mixed struct {
+		TestPromotedDeep
+		TestPromotedOther
+	}
+	type conflict struct {
+		TestPromotedName
+		TestPromotedOther
+	}
+
+	tests := []struct {
+		name    string
+		doc     any
+		want    map[string]string
+		missing []string
+	}{
+		{
+			name: "Outer first",
+			doc:  nameFirst{Name: "outer", TestPromotedName: TestPromotedName{Name: "inner", City: "Paris"}},
+			want: map[string]string{"name": "outer", "city": "Paris"},
+		},
+		{
+			name: "Embedded first",
+			doc:  embeddedFirst{TestPromotedName: TestPromotedName{Name: "inner", City: "Paris"}, Name: "outer"},
+			want: map[string]string{"name": "outer", "city": "Paris"},
+		},
+		{
+			name: "Shallower embedded",
+			doc: mixed{
+				TestPromotedDeep:  TestPromotedDeep{TestPromotedName{Name: "deep", City: "Paris"}},
+				TestPromotedOther: TestPromotedOther{City: "Rome", Zip: "00100"},
+			},
+			want: map[string]string{"name": "deep", "city": "Rome", "zip": "00100"},
+		},
+		{
+			name: "Same depth",
+			doc: conflict{
+				TestPromotedName:  TestPromotedName{Name: "inner", City: "Paris"},
+				TestPromotedOther: TestPromotedOther{City: "Rome", Zip: "00100"},
+			},
+			want:    map[string]string{"name": "inner", "zip": "00100"},
+			missing: []string{"city"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			structure, err := getStructure(tt.doc, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, value := range tt.want {
+				field, ok := structure[name]
+				if !ok {
+					t.Errorf("expected field %s", name)
+					continue
+				}
+				if field.Value() != value {
+					t.Errorf("expected %s to be %s, got %v", name, value, field.Value())
+				}
+			}
+			for _, name := range tt.missing {
+				if _, ok := structure[name]; ok {
+					t.Errorf("expected conflicting field %s to be dropped", name)
+				}
+			}
+		})
+	}
+}
+
+func TestGetStructure_nil(t *testing.T) {
+	var account *TestAccount
+	if _, err := getStructure(account, ""); err == nil {
+		t.Errorf("expected nil pointer to error")
+	}
+}
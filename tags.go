@@ -0,0 +1,230 @@
+package gofindit
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/brianvoe/gofindit/fields"
+	"github.com/brianvoe/gofindit/tokenizers"
+	"golang.org/x/text/language"
+)
+
+// tagOptions are the index options for a struct field parsed from its find tag
+//
+//	find:"birthday,type=date,granularity=month,boost=2,index=false,sortable"
+//
+// The first value is the name and the rest are options. Options
+// not listed below are passed to fields.GetField as its config,
+// which returns an error for options the field does not use.
+// Multi fields index the value again under name.sub and are listed
+// as sub, sub:type or sub:type:analyzer separated by a pipe
+//
+//	find:"name,fields=keyword|ngram|autocomplete:text:edge_ngram"
+//
+// Text and keywords sort by their bytes unless they have a collate
+// language tag and an optional strength
+//
+//	find:"name,collate=de,strength=secondary"
+type tagOptions struct {
+	Name     string         // Field name, defaults to the struct field name
+	Type     string         // Registered field type, defaults to one for the go type
+	Analyzer string         // Registered tokenizer for text fields
+	Boost    float64        // Multiplies the score of matches on the field, defaults to 1
+	Index    bool           // False keeps the value in the document without indexing it
+	Sortable bool           // Keeps text values for sorting
+	Collate  string         // Language tag text and keywords are sorted by, like de or sv
+	Strength string         // Collation strength, primary, secondary or tertiary
+	Config   map[string]any // Options passed to fields.GetField
+	Fields   []tagOptions   // Multi fields, each named by its sub name
+}
+
+// tagCache holds the parsed tag options for each struct type
+var tagCache sync.Map // map[reflect.Type][]tagOptions
+
+// parseTag parses a find tag into its options
+func parseTag(tag string) (tagOptions, error) {
+	parts := strings.Split(tag, ",")
+	options := tagOptions{
+		Name:   strings.TrimSpace(parts[0]),
+		Boost:  1,
+		Index:  true,
+		Config: make(map[string]any),
+	}
+
+	for _, part := range parts[1:] {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(part), "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+
+		switch key {
+		case "type":
+			options.Type = value
+		case "analyzer":
+			if _, err := tokenizers.GetTokenizer(value, nil); err != nil {
+				return options, fmt.Errorf("invalid analyzer in tag %q: %v", tag, err)
+			}
+			options.Analyzer = value
+		case "boost":
+			boost, err := strconv.ParseFloat(value, 64)
+			if err != nil || boost <= 0 {
+				return options, fmt.Errorf("invalid boost in tag %q", tag)
+			}
+			options.Boost = boost
+		case "fields":
+			for _, sub := range strings.Split(value, "|") {
+				subOptions, err := parseSubField(strings.TrimSpace(sub))
+				if err != nil {
+					return options, fmt.Errorf("invalid fields in tag %q: %v", tag, err)
+				}
+				options.Fields = append(options.Fields, subOptions)
+			}
+		case "collate":
+			if _, err := language.Parse(value); err != nil {
+				return options, fmt.Errorf("invalid collate in tag %q: %v", tag, err)
+			}
+			options.Collate = value
+		case "strength":
+			if !isValidStrength(value) {
+				return options, fmt.Errorf("invalid strength in tag %q", tag)
+			}
+			options.Strength = value
+		case "index", "sortable":
+			flag := true
+			if hasValue {
+				var err error
+				flag, err = strconv.ParseBool(value)
+				if err != nil {
+					return options, fmt.Errorf("invalid %s in tag %q", key, tag)
+				}
+			}
+
+			if key == "index" {
+				options.Index = flag
+			} else {
+				options.Sortable = flag
+			}
+		default:
+			// Options without a value are flags
+			if !hasValue {
+				options.Config[key] = true
+				continue
+			}
+			options.Config[key] = parseTagValue(value)
+		}
+	}
+
+	if options.Analyzer != "" {
+		options.Config["analyzer"] = options.Analyzer
+	}
+
+	// Collated values are always kept for sorting
+	if options.Strength != "" && options.Collate == "" {
+		return options, fmt.Errorf("strength requires collate in tag %q", tag)
+	}
+	if options.Collate != "" {
+		options.Sortable = true
+	}
+
+	return options, nil
+}
+
+// parseSubField parses a multi field as sub, sub:type or sub:type:analyzer.
+// A sub without a type uses the field type of the same name, like
+// keyword, or a text field analyzed by the tokenizer of the same name
+func parseSubField(sub string) (tagOptions, error) {
+	parts := strings.Split(sub, ":")
+	if parts[0] == "" || len(parts) > 3 {
+		return tagOptions{}, fmt.Errorf("invalid multi field %q", sub)
+	}
+
+	options := tagOptions{
+		Name:   parts[0],
+		Boost:  1,
+		Index:  true,
+		Config: make(map[string]any),
+	}
+	if len(parts) > 1 {
+		options.Type = parts[1]
+	}
+	if len(parts) > 2 {
+		options.Analyzer = parts[2]
+	}
+
+	if options.Type == "" {
+		if _, err := tokenizers.GetTokenizer(options.Name, nil); err == nil {
+			options.Type = fields.DefaultText
+			options.Analyzer = options.Name
+		} else {
+			options.Type = options.Name
+		}
+	}
+
+	if options.Analyzer != "" {
+		options.Config["analyzer"] = options.Analyzer
+	}
+
+	// Make sure the field can be created with the options
+	if _, err := fields.GetField(options.Type, options.Config); err != nil {
+		return tagOptions{}, err
+	}
+
+	// Keywords are for sorting
+	options.Sortable = options.Type == fields.DefaultKeyword
+
+	return options, nil
+}
+
+// parseTagValue converts a tag option value to an int,
+// float64 or bool if it is one, otherwise it stays a string
+func parseTagValue(value string) any {
+	if i, err := strconv.Atoi(value); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+
+	return value
+}
+
+// typeTagOptions returns the tag options for each field of the struct
+// type. Tags are only parsed the first time a type is seen
+func typeTagOptions(t reflect.Type) ([]tagOptions, error) {
+	if cached, ok := tagCache.Load(t); ok {
+		return cached.([]tagOptions), nil
+	}
+
+	options := make([]tagOptions, t.NumField())
+	for i := range options {
+		field := t.Field(i)
+
+		var err error
+		options[i], err = parseTag(field.Tag.Get("find"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+
+		// The field tag can still set the type
+		if options[i].Type == "" {
+			options[i].Type = field.Tag.Get("field")
+		}
+	}
+
+	tagCache.Store(t, options)
+	return options, nil
+}
+
+// tagName returns the name portion of a find tag
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,275 @@
+package gofindit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofindit/fields"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    tagOptions
+		wantErr bool
+	}{
+		{
+			tag:  "name",
+			want: tagOptions{Name: "name", Boost: 1, Index: true, Config: map[string]any{}},
+		},
+		{
+			tag: "birthday,type=date,granularity=month,boost=2,index=false,sortable",
+			want: tagOptions{
+				Name: "birthday", Type: "date", Boost: 2, Index: false, Sortable: true,
+				Config: map[string]any{"granularity": "month"},
+			},
+		},
+		{
+			tag: ",min=2,max=3.5,edge,analyzer=words",
+			want: tagOptions{
+				Boost: 1, Index: true, Analyzer: "words",
+				Config: map[string]any{"min": 2, "max": 3.5, "edge": true, "analyzer": "words"},
+			},
+		},
+		{
+			tag:  "bio, sortable=false , index",
+			want: tagOptions{Name: "bio", Boost: 1, Index: true, Config: map[string]any{}},
+		},
+		{
+			tag: "name,fields=keyword|ngram|auto:text:edge_ngram",
+			want: tagOptions{
+				Name: "name", Boost: 1, Index: true, Config: map[string]any{},
+				Fields: []tagOptions{
+					{Name: "keyword", Type: "keyword", Boost: 1, Index: true, Sortable: true, Config: map[string]any{}},
+					{Name: "ngram", Type: "text", Analyzer: "ngram", Boost: 1, Index: true, Config: map[string]any{"analyzer": "ngram"}},
+					{Name: "auto", Type: "text", Analyzer: "edge_ngram", Boost: 1, Index: true, Config: map[string]any{"analyzer": "edge_ngram"}},
+				},
+			},
+		},
+		{
+			tag: "name,collate=de,strength=primary",
+			want: tagOptions{
+				Name: "name", Boost: 1, Index: true, Sortable: true, Collate: "de", Strength: "primary",
+				Config: map[string]any{},
+			},
+		},
+		{tag: "name,fields=nope", wantErr: true},
+		{tag: "name,fields=a:b:c:d", wantErr: true},
+		{tag: "age,boost=0", wantErr: true},
+		{tag: "age,boost=high", wantErr: true},
+		{tag: "age,index=maybe", wantErr: true},
+		{tag: "age,analyzer=nope", wantErr: true},
+		{tag: "name,collate=not a language", wantErr: true},
+		{tag: "name,collate=de,strength=quaternary", wantErr: true},
+		{tag: "name,strength=primary", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := parseTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeTagOptions(t *testing.T) {
+	type Test struct {
+		Name     string          `find:"name,boost=3"`
+		Location fields.GeoPoint `find:"location,precision=5"`
+		Legacy   string          `find:"legacy" field:"ip"`
+	}
+
+	options, err := typeTagOptions(reflect.TypeOf(Test{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options[0].Boost != 3 {
+		t.Errorf("expected boost 3, got %v", options[0].Boost)
+	}
+	if options[2].Type != "ip" {
+		t.Errorf("expected field tag type ip, got %s", options[2].Type)
+	}
+
+	// Parsed once per type
+	if _, ok := tagCache.Load(reflect.TypeOf(Test{})); !ok {
+		t.Errorf("expected options to be cached")
+	}
+}
+
+func TestGetStructure_tagConfig(t *testing.T) {
+	type Test struct {
+		Location fields.GeoPoint `find:"location,precision=5"`
+		Server   string          `find:"server,type=ip"`
+		Internal fields.GeoPoint `find:"internal,index=false"`
+	}
+
+	s, err := newStructure(Test{Server: "10.0.0.1"}, "")
+	if err != nil {
+		t.Fatalf("Failed to get structure: %v", err)
+	}
+
+	// Tag options are passed to the field config
+	location, ok := s.fields["location"].(*fields.Geo)
+	if !ok {
+		t.Fatalf("expected location to be a geo field")
+	}
+	if got, _ := location.ToSearchBytes(fields.GeoPoint{Lat: 1, Lon: 1}); len(got) != 5 {
+		t.Errorf("expected geohash precision 5, got %s", got)
+	}
+
+	if _, ok := s.fields["server"].(*fields.IP); !ok {
+		t.Errorf("expected server to be an ip field")
+	}
+
+	// Not indexed
+	if _, found := s.fields["internal"]; found {
+		t.Errorf("expected internal to not be indexed")
+	}
+}
+
+func TestIndex_Search_tagNGramConfig(t *testing.T) {
+	type Test struct {
+		Name string   `find:"name,analyzer=ngram,min=2,max=4"`
+		Tags []string `find:"tags,analyzer=ngram,min=2,edge"`
+	}
+
+	index := New()
+	if err := index.Index("1", Test{Name: "Abby Smith", Tags: []string{"rock", "chess"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field string
+		value string
+		want  int
+	}{
+		{"name", "ab", 1},
+		{"name", "mi", 1},
+		{"name", "xy", 0},
+		{"tags", "ro", 1},
+		{"tags", "ch", 1},
+		{"tags", "ck", 0},
+	}
+
+	for _, tt := range tests {
+		results, err := index.Search(SearchQuery{
+			Fields: []SearchQueryField{{Field: tt.field, Type: "match", Value: tt.value}},
+		})
+		if err != nil {
+			t.Fatalf("search %s %q: %v", tt.field, tt.value, err)
+		}
+		if len(results) != tt.want {
+			t.Errorf("expected %d results for %s %q, got %d", tt.want, tt.field, tt.value, len(results))
+		}
+	}
+}
+
+func TestIndex_unknownTagOption(t *testing.T) {
+	tests := []any{
+		struct {
+			Born time.Time `find:"born,granulrity=month"`
+		}{},
+		struct {
+			Name string `find:"name,mni=2"`
+		}{},
+		struct {
+			Name string `find:"name,min=2"`
+		}{},
+		struct {
+			Age int `find:"age,lowercase"`
+		}{},
+	}
+
+	for _, doc := range tests {
+		if err := New().Index("1", doc); err == nil {
+			t.Errorf("expected an error for the options of %+v", reflect.TypeOf(doc))
+		}
+	}
+}
+
+func TestIndex_Search_tagBoost(t *testing.T) {
+	type TitleBoost struct {
+		Title string `find:"title,boost=3"`
+		Body  string `find:"body"`
+	}
+	type BodyBoost struct {
+		Title string `find:"title"`
+		Body  string `find:"body,boost=3"`
+	}
+
+	long := "red shoes for running in the rain"
+	tests := []struct {
+		name string
+		a, b any // a matches best on the title and b on the body
+		want string
+	}{
+		{"title", TitleBoost{Title: "red", Body: long}, TitleBoost{Title: long, Body: "red"}, "a"},
+		{"body", BodyBoost{Title: "red", Body: long}, BodyBoost{Title: long, Body: "red"}, "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := New()
+			if err := index.Index("a", tt.a); err != nil {
+				t.Fatal(err)
+			}
+			if err := index.Index("b", tt.b); err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := index.SearchHits(SearchQuery{
+				Fields: []SearchQueryField{
+					{Field: "title", Type: "match", Value: "red"},
+					{Field: "body", Type: "match", Value: "red"},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Hits) != 2 {
+				t.Fatalf("expected 2 hits, got %d", len(response.Hits))
+			}
+
+			// The boosted field decides which scores higher
+			if response.Hits[0].ID != tt.want || response.Hits[0].Score <= response.Hits[1].Score {
+				t.Errorf("expected %s to score highest, got %s %v and %s %v", tt.want,
+					response.Hits[0].ID, response.Hits[0].Score, response.Hits[1].ID, response.Hits[1].Score)
+			}
+		})
+	}
+}
+
+func TestGetStructure_multiFields(t *testing.T) {
+	type Name string
+	type Test struct {
+		Name Name `find:"name,fields=keyword|ngram"`
+	}
+
+	s, err := newStructure(Test{Name: "Billy Smith"}, "")
+	if err != nil {
+		t.Fatalf("Failed to get structure: %v", err)
+	}
+
+	if _, ok := s.fields["name"].(*fields.Text); !ok {
+		t.Errorf("expected name to be a text field")
+	}
+	if _, ok := s.fields["name.keyword"].(*fields.Keyword); !ok {
+		t.Errorf("expected name.keyword to be a keyword field")
+	}
+
+	ngram, ok := s.fields["name.ngram"].(*fields.Text)
+	if !ok {
+		t.Fatalf("expected name.ngram to be a text field")
+	}
+	searchBytes, _ := ngram.ToSearchBytes("mit")
+	if match, _ := ngram.Search(searchBytes); !match {
+		t.Errorf("expected name.ngram to match mit")
+	}
+}
@@ -0,0 +1,118 @@
+package charfilters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"runtime"
+)
+
+// Func is a char filter that runs on the raw text before it is tokenized.
+// It returns the filtered text and the offsets that map it back to the input
+type Func func(text string) (string, Offsets, error)
+
+// Offsets maps every byte offset in filtered text, including the
+// end of the text, back to the byte offset in the text it came from
+type Offsets []int
+
+// Original returns the offset in the original text for
+// the given offset in the filtered text
+func (o Offsets) Original(offset int) int {
+	if len(o) == 0 {
+		return offset
+	}
+	if offset < 0 {
+		return o[0]
+	}
+	if offset >= len(o) {
+		return o[len(o)-1]
+	}
+	return o[offset]
+}
+
+// identity returns offsets that map text directly onto itself
+func identity(text string) Offsets {
+	offsets := make(Offsets, len(text)+1)
+	for i := range offsets {
+		offsets[i] = i
+	}
+	return offsets
+}
+
+// Apply runs the char filters in order and returns the
+// filtered text with offsets back into the original text
+func Apply(text string, filters ...Func) (string, Offsets, error) {
+	offsets := identity(text)
+
+	for _, filter := range filters {
+		out, outOffsets, err := filter(text)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// Chain the new offsets through the previous ones
+		combined := make(Offsets, len(outOffsets))
+		for i, offset := range outOffsets {
+			combined[i] = offsets.Original(offset)
+		}
+
+		text, offsets = out, combined
+	}
+
+	return text, offsets, nil
+}
+
+// FuncsID returns a short id for the given char filters
+func FuncsID(filters ...Func) string {
+	// if no filters, return empty string
+	if len(filters) == 0 {
+		return ""
+	}
+
+	identifierStr := ""
+	for _, filter := range filters {
+		funcPtr := reflect.ValueOf(filter).Pointer()
+		funcName := runtime.FuncForPC(funcPtr).Name()
+
+		identifierStr += funcName + ";"
+	}
+
+	// Use SHA-256 and then truncate.
+	hasher := sha256.New()
+	hasher.Write([]byte(identifierStr))
+	fullHash := hasher.Sum(nil)
+	// Truncate to the first 12 characters.
+	truncatedHash := hex.EncodeToString(fullHash)[:12]
+
+	return truncatedHash
+}
+
+// builder writes filtered text while keeping
+// track of where each byte came from
+type builder struct {
+	out     []byte
+	offsets Offsets
+}
+
+// write appends str to the output with every byte
+// pointing at the given offset in the input
+func (b *builder) write(str string, offset int) {
+	for i := 0; i < len(str); i++ {
+		b.out = append(b.out, str[i])
+		b.offsets = append(b.offsets, offset)
+	}
+}
+
+// copy appends the input from start to end unchanged
+func (b *builder) copy(text string, start, end int) {
+	for i := start; i < end; i++ {
+		b.out = append(b.out, text[i])
+		b.offsets = append(b.offsets, i)
+	}
+}
+
+// finish closes out the offsets with the end of the input
+func (b *builder) finish(end int) (string, Offsets) {
+	b.offsets = append(b.offsets, end)
+	return string(b.out), b.offsets
+}
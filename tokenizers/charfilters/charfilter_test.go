@@ -0,0 +1,50 @@
+package charfilters
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	input := "<b>caf&eacute;</b> &amp; bar"
+
+	lower := Mapping(map[string]string{"é": "e"})
+	out, offsets, err := Apply(input, HTMLStrip, lower)
+	if err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	if out != "cafe & bar" {
+		t.Errorf("Apply() = %q, want %q", out, "cafe & bar")
+	}
+
+	// Every output offset plus the end should be mapped
+	if len(offsets) != len(out)+1 {
+		t.Errorf("Apply() offsets length = %d, want %d", len(offsets), len(out)+1)
+	}
+
+	tests := []struct {
+		offset int
+		want   int
+	}{
+		{0, 3},                     // c
+		{3, 6},                     // e from &eacute;
+		{5, 19},                    // & from &amp;
+		{7, 25},                    // b
+		{len(out), len(input)},     // end
+		{len(out) + 5, len(input)}, // past the end
+	}
+
+	for _, tt := range tests {
+		if got := offsets.Original(tt.offset); got != tt.want {
+			t.Errorf("Offsets.Original(%d) = %d, want %d", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestFuncsID(t *testing.T) {
+	if FuncsID() != "" {
+		t.Errorf("Expected empty id for no filters")
+	}
+
+	if FuncsID(HTMLStrip) == FuncsID(HTMLStrip, HTMLStrip) {
+		t.Errorf("Expected different ids for different filters")
+	}
+}
@@ -0,0 +1,125 @@
+package charfilters
+
+import (
+	"html"
+	"strings"
+)
+
+// blockTags are replaced with a space so the
+// words on either side of them are not joined
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "header": true, "hr": true, "li": true, "main": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// skipTags have their content removed along with the tag
+var skipTags = map[string]bool{
+	"script": true, "style": true,
+}
+
+// HTMLStrip removes html tags and comments, drops the content
+// of script and style elements and decodes html entities
+func HTMLStrip(text string) (string, Offsets, error) {
+	b := builder{}
+
+	i := 0
+	for i < len(text) {
+		switch text[i] {
+		case '<':
+			// Comments
+			if strings.HasPrefix(text[i:], "<!--") {
+				end := strings.Index(text[i+4:], "-->")
+				if end < 0 {
+					i = len(text)
+				} else {
+					i += 4 + end + 3
+				}
+				continue
+			}
+
+			end := strings.IndexByte(text[i:], '>')
+			name, closing, ok := tagName(text[i:])
+			if end < 0 || !ok {
+				// Not a tag so leave the < in the text
+				b.copy(text, i, i+1)
+				i++
+				continue
+			}
+
+			if blockTags[name] {
+				b.write(" ", i)
+			}
+			i += end + 1
+
+			// Skip everything until the closing tag
+			if skipTags[name] && !closing {
+				closeTag := strings.Index(strings.ToLower(text[i:]), "</"+name)
+				if closeTag < 0 {
+					i = len(text)
+					continue
+				}
+				i += closeTag
+			}
+		case '&':
+			end := strings.IndexByte(text[i:], ';')
+			if end < 0 || end > 32 {
+				b.copy(text, i, i+1)
+				i++
+				continue
+			}
+
+			entity := text[i : i+end+1]
+			decoded := html.UnescapeString(entity)
+			if decoded == entity {
+				// Unknown entity so leave it as is
+				b.copy(text, i, i+1)
+				i++
+				continue
+			}
+
+			b.write(decoded, i)
+			i += end + 1
+		default:
+			b.copy(text, i, i+1)
+			i++
+		}
+	}
+
+	out, offsets := b.finish(len(text))
+	return out, offsets, nil
+}
+
+// tagName returns the lowercased name of the tag at the start
+// of str and whether it is a closing tag
+func tagName(str string) (string, bool, bool) {
+	str = str[1:]
+	closing := strings.HasPrefix(str, "/")
+	if closing {
+		str = str[1:]
+	}
+
+	end := 0
+	for end < len(str) {
+		c := str[end]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (end > 0 && c >= '0' && c <= '9') {
+			end++
+			continue
+		}
+		break
+	}
+
+	// Doctypes and other declarations are treated as tags
+	if end == 0 && !closing && strings.HasPrefix(str, "!") {
+		return "!", false, true
+	}
+
+	if end == 0 {
+		return "", closing, false
+	}
+
+	return strings.ToLower(str[:end]), closing, true
+}
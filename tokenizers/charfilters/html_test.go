@@ -0,0 +1,67 @@
+package charfilters
+
+import "testing"
+
+func TestHTMLStrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "plain",
+			input: "hello world",
+			want:  "hello world",
+		},
+		{
+			name:  "inline tags",
+			input: `<a href="/x">hello</a> <b>world</b>`,
+			want:  "hello world",
+		},
+		{
+			name:  "block tags",
+			input: "<p>hello</p><p>world</p>",
+			want:  " hello  world ",
+		},
+		{
+			name:  "entities",
+			input: "Tom &amp; Jerry &lt;3 &#39;cat&#39; &unknown;",
+			want:  "Tom & Jerry <3 'cat' &unknown;",
+		},
+		{
+			name:  "script and style",
+			input: "a<script>alert('x')</script>b<style>p{}</style>c",
+			want:  "abc",
+		},
+		{
+			name:  "comments",
+			input: "a<!-- <p>hidden</p> -->b",
+			want:  "ab",
+		},
+		{
+			name:  "not a tag",
+			input: "1 < 2 and 3 > 2",
+			want:  "1 < 2 and 3 > 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, offsets, err := HTMLStrip(tt.input)
+			if err != nil {
+				t.Fatalf("HTMLStrip() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HTMLStrip() = %q, want %q", got, tt.want)
+			}
+			if len(offsets) != len(got)+1 {
+				t.Errorf("HTMLStrip() offsets length = %d, want %d", len(offsets), len(got)+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package charfilters
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PatternReplace returns a char filter that replaces every match of the
+// regular expression with the replacement. The replacement can reference
+// capture groups the same way as regexp.Regexp.Expand, like "$1"
+func PatternReplace(pattern string, replacement string) (Func, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(text string) (string, Offsets, error) {
+		b := builder{}
+
+		last := 0
+		for _, match := range re.FindAllStringSubmatchIndex(text, -1) {
+			b.copy(text, last, match[0])
+
+			expanded := re.ExpandString(nil, replacement, text, match)
+			b.write(string(expanded), match[0])
+
+			last = match[1]
+		}
+		b.copy(text, last, len(text))
+
+		out, offsets := b.finish(len(text))
+		return out, offsets, nil
+	}, nil
+}
+
+// Mapping returns a char filter that replaces each key found in
+// the text with its value. Longer keys are matched first
+func Mapping(mappings map[string]string) Func {
+	keys := make([]string, 0, len(mappings))
+	for key := range mappings {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	// Longest keys first so "ae" wins over "a"
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	return func(text string) (string, Offsets, error) {
+		b := builder{}
+
+		i := 0
+	outer:
+		for i < len(text) {
+			for _, key := range keys {
+				if strings.HasPrefix(text[i:], key) {
+					b.write(mappings[key], i)
+					i += len(key)
+					continue outer
+				}
+			}
+
+			b.copy(text, i, i+1)
+			i++
+		}
+
+		out, offsets := b.finish(len(text))
+		return out, offsets, nil
+	}
+}
@@ -0,0 +1,83 @@
+package charfilters
+
+import "testing"
+
+func TestPatternReplace(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		replacement string
+		input       string
+		want        string
+	}{
+		{
+			name:        "markdown links",
+			pattern:     `\[([^\]]*)\]\([^)]*\)`,
+			replacement: "$1",
+			input:       "see [the docs](http://example.com) now",
+			want:        "see the docs now",
+		},
+		{
+			name:        "markdown emphasis",
+			pattern:     `[*_]+`,
+			replacement: "",
+			input:       "**bold** and _italic_",
+			want:        "bold and italic",
+		},
+		{
+			name:        "no match",
+			pattern:     `\d+`,
+			replacement: "#",
+			input:       "no numbers",
+			want:        "no numbers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := PatternReplace(tt.pattern, tt.replacement)
+			if err != nil {
+				t.Fatalf("PatternReplace() failed: %v", err)
+			}
+
+			got, offsets, err := filter(tt.input)
+			if err != nil {
+				t.Fatalf("PatternReplace() filter failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PatternReplace() = %q, want %q", got, tt.want)
+			}
+			if len(offsets) != len(got)+1 {
+				t.Errorf("PatternReplace() offsets length = %d, want %d", len(offsets), len(got)+1)
+			}
+		})
+	}
+
+	// Invalid patterns should error
+	if _, err := PatternReplace("(", ""); err == nil {
+		t.Errorf("PatternReplace() expected error for invalid pattern")
+	}
+}
+
+func TestMapping(t *testing.T) {
+	filter := Mapping(map[string]string{
+		"a":  "1",
+		"ae": "æ",
+		"ß":  "ss",
+	})
+
+	got, offsets, err := filter("aeab große")
+	if err != nil {
+		t.Fatalf("Mapping() failed: %v", err)
+	}
+
+	want := "æ1b grosse"
+	if got != want {
+		t.Errorf("Mapping() = %q, want %q", got, want)
+	}
+
+	// The "b" after the replaced characters should still point at its original position
+	if offsets.Original(3) != 3 {
+		t.Errorf("Offsets.Original(3) = %d, want %d", offsets.Original(3), 3)
+	}
+}
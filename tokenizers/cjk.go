@@ -0,0 +1,110 @@
+package tokenizers
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+func init() {
+	SetTokenizer("cjk", NewCJK())
+}
+
+// CJK splits chinese, japanese and korean text into overlapping
+// bigrams so a sentence without spaces can be searched per word.
+// Everything else is split on UAX#29 word boundaries
+type CJK struct {
+	words []string
+}
+
+// NewCJK returns a new CJK bigram tokenizer
+func NewCJK() *CJK {
+	return &CJK{}
+}
+
+// Copy returns a new CJK tokenizer
+func (c *CJK) Copy() Tokenizer {
+	return NewCJK()
+}
+
+// Process will take in a string value and
+// use it to fill out the struct fields
+func (c *CJK) Process(str string) error {
+	var err error
+	c.words, err = c.ToSearch(str)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ToSearch will return the words to search
+func (c *CJK) ToSearch(str string) ([]string, error) {
+	if str == "" {
+		return nil, errors.New("empty string")
+	}
+
+	var tokens []string
+	var run []string // Current run of touching cjk characters
+	runEnd := -1
+
+	flush := func() {
+		if len(run) > 0 {
+			tokens = append(tokens, bigrams(run)...)
+		}
+		run = nil
+	}
+
+	for _, seg := range segmentWords(str) {
+		if isCJK(seg) {
+			// Only characters that touch each other are bigrammed together
+			if seg.start != runEnd {
+				flush()
+			}
+			run = append(run, clusters(strings.ToLower(seg.text))...)
+			runEnd = seg.end
+			continue
+		}
+		flush()
+
+		// Dictionary free fallback for scripts without spaces
+		if seg.class == wbComplex {
+			tokens = append(tokens, bigrams(clusters(seg.text))...)
+			continue
+		}
+
+		tokens = append(tokens, normalizeToken(seg.text))
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// Search checks if the provided words appear in order within the words
+func (c *CJK) Search(val []string) (bool, error) {
+	return searchInOrder(c.words, val), nil
+}
+
+// Score scores the words for the search tokens
+func (c *CJK) Score(val []string) float64 {
+	return scoreTokens(c.words, val)
+}
+
+// isCJK checks if the segment is made up of cjk characters. It
+// needs at least one so numbers and punctuation are left alone
+func isCJK(seg segment) bool {
+	if seg.class == wbKatakana || seg.class == wbIdeographic {
+		return true
+	}
+
+	found := false
+	for _, r := range seg.text {
+		if unicode.In(r, cjkScripts...) {
+			found = true
+		} else if unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return found
+}
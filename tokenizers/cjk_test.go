@@ -0,0 +1,104 @@
+package tokenizers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCJKToSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			text:    "",
+			wantErr: true,
+		},
+		{
+			name: "chinese",
+			text: "我爱北京",
+			want: []string{"我爱", "爱北", "北京"},
+		},
+		{
+			name: "japanese",
+			text: "東京タワー",
+			want: []string{"東京", "京タ", "タワ", "ワー"},
+		},
+		{
+			name: "korean",
+			text: "한국어",
+			want: []string{"한국", "국어"},
+		},
+		{
+			name: "single",
+			text: "猫",
+			want: []string{"猫"},
+		},
+		{
+			name: "mixed",
+			text: "Sony 相机 A7",
+			want: []string{"sony", "相机", "a7"},
+		},
+		{
+			name: "spaces split runs",
+			text: "東京 大阪",
+			want: []string{"東京", "大阪"},
+		},
+		{
+			name: "numbers",
+			text: "3.14 と 2024年",
+			want: []string{"3.14", "と", "2024", "年"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCJK()
+			got, err := c.ToSearch(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CJK.ToSearch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CJK.ToSearch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCJKSearch(t *testing.T) {
+	c := NewCJK()
+	if err := c.Process("索尼数码相机 Alpha"); err != nil {
+		t.Fatalf("CJK.Process() failed: %v", err)
+	}
+
+	tests := []struct {
+		search string
+		match  bool
+	}{
+		{"相机", true},
+		{"数码相机", true},
+		{"alpha", true},
+		{"手机", false},
+	}
+
+	for _, tt := range tests {
+		val, err := c.ToSearch(tt.search)
+		if err != nil {
+			t.Errorf("CJK.ToSearch() failed: %v", err)
+		}
+
+		match, err := c.Search(val)
+		if err != nil {
+			t.Errorf("CJK.Search() failed: %v", err)
+		}
+
+		if match != tt.match {
+			t.Errorf("CJK.Search(%q) = %v, want %v", tt.search, match, tt.match)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package tokenizers
+
+import (
+	"github.com/brianvoe/gofindit/tokenizers/charfilters"
+)
+
+func init() {
+	SetTokenizer("html", NewFiltered(NewWords(), charfilters.HTMLStrip))
+}
+
+// Filtered runs char filters on the raw text before
+// passing it along to the wrapped tokenizer
+type Filtered struct {
+	tokenizer Tokenizer
+	filters   []charfilters.Func
+	filtered  string
+	offsets   charfilters.Offsets
+}
+
+// Offsetter is implemented by tokenizers that change the value before
+// it is tokenized, like Filtered, so positions in the text they
+// tokenized can be mapped back to the value they processed
+type Offsetter interface {
+	Filtered() string
+	Offsets() charfilters.Offsets
+}
+
+// NewFiltered returns a tokenizer that runs the char
+// filters, in order, ahead of the given tokenizer
+func NewFiltered(tokenizer Tokenizer, filters ...charfilters.Func) *Filtered {
+	return &Filtered{
+		tokenizer: tokenizer,
+		filters:   filters,
+	}
+}
+
+// Copy returns a new Filtered tokenizer with the same filters
+// and a copy of the wrapped tokenizer when it can be copied
+func (f *Filtered) Copy() Tokenizer {
+	tokenizer := f.tokenizer
+	if copier, ok := tokenizer.(Copier); ok {
+		tokenizer = copier.Copy()
+	}
+
+	return NewFiltered(tokenizer, f.filters...)
+}
+
+// Process will run the char filters and pass the
+// filtered text to the wrapped tokenizer
+func (f *Filtered) Process(val string) error {
+	filtered, offsets, err := charfilters.Apply(val, f.filters...)
+	if err != nil {
+		return err
+	}
+	f.filtered = filtered
+	f.offsets = offsets
+
+	return f.tokenizer.Process(filtered)
+}
+
+// ToSearch will run the same char filters on the
+// search value so both sides are tokenized alike
+func (f *Filtered) ToSearch(val string) ([]string, error) {
+	filtered, _, err := charfilters.Apply(val, f.filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.tokenizer.ToSearch(filtered)
+}
+
+// Search passes the search values to the wrapped tokenizer
+func (f *Filtered) Search(val []string) (bool, error) {
+	return f.tokenizer.Search(val)
+}
+
+// Score passes the search values to the wrapped tokenizer, if it
+// cannot score them the value scores 1 when it matches
+func (f *Filtered) Score(val []string) float64 {
+	if scorer, ok := f.tokenizer.(Scorer); ok {
+		return scorer.Score(val)
+	}
+
+	if matched, _ := f.tokenizer.Search(val); matched {
+		return 1
+	}
+	return 0
+}
+
+// Filtered returns the processed value after the char filters
+func (f *Filtered) Filtered() string {
+	return f.filtered
+}
+
+// Offsets returns the offsets of the processed value so positions
+// in the filtered text can be mapped to the original. Text fields
+// process each value with its own copy so each keeps its offsets
+func (f *Filtered) Offsets() charfilters.Offsets {
+	return f.offsets
+}
@@ -0,0 +1,55 @@
+package tokenizers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brianvoe/gofindit/tokenizers/charfilters"
+)
+
+func TestFilteredHTML(t *testing.T) {
+	words := NewWords()
+	f := NewFiltered(words, charfilters.HTMLStrip)
+
+	input := `<div class="bio"><p>Tom &amp; Jerry</p><script>var href = 1;</script></div>`
+	if err := f.Process(input); err != nil {
+		t.Fatalf("Filtered.Process() failed: %v", err)
+	}
+
+	want := []string{"tom", "jerry"}
+	if !reflect.DeepEqual(words.words, want) {
+		t.Errorf("Filtered.Process() = %v, want %v", words.words, want)
+	}
+
+	// Tag names should not be searchable
+	for _, search := range []string{"div", "href", "amp"} {
+		val, err := f.ToSearch(search)
+		if err != nil {
+			t.Fatalf("Filtered.ToSearch() failed: %v", err)
+		}
+
+		match, err := f.Search(val)
+		if err != nil {
+			t.Fatalf("Filtered.Search() failed: %v", err)
+		}
+		if match {
+			t.Errorf("Filtered.Search(%q) matched, want no match", search)
+		}
+	}
+
+	// Offsets should point back into the original text
+	filtered, _, _ := charfilters.Apply(input, charfilters.HTMLStrip)
+	start := f.Offsets().Original(indexOf(filtered, "Jerry"))
+	if input[start:start+5] != "Jerry" {
+		t.Errorf("Filtered.Offsets() pointed at %q, want %q", input[start:start+5], "Jerry")
+	}
+}
+
+func indexOf(str, sub string) int {
+	for i := 0; i+len(sub) <= len(str); i++ {
+		if str[i:i+len(sub)] == sub {
+			return i
+		}
+	}
+	return -1
+}
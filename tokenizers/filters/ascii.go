@@ -0,0 +1,57 @@
+package filters
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// asciiFolders hold chains that remove accents by splitting runes into
+// their base and combining marks, dropping the marks and recombining. A
+// chain keeps state while it runs so each call takes its own
+var asciiFolders = sync.Pool{
+	New: func() any {
+		return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	},
+}
+
+// asciiReplacer handles letters and symbols that
+// do not decompose into an ascii base letter
+var asciiReplacer = strings.NewReplacer(
+	"ß", "ss", "ẞ", "SS",
+	"æ", "ae", "Æ", "AE",
+	"œ", "oe", "Œ", "OE",
+	"ø", "o", "Ø", "O",
+	"ł", "l", "Ł", "L",
+	"đ", "d", "Đ", "D",
+	"ð", "d", "Ð", "D",
+	"þ", "th", "Þ", "TH",
+	"ı", "i", "ħ", "h", "Ħ", "H",
+	"ŋ", "n", "Ŋ", "N",
+	"ſ", "s",
+	"‘", "'", "’", "'", "‚", "'", "‛", "'",
+	"“", "\"", "”", "\"", "„", "\"",
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-",
+	"…", "...",
+)
+
+// ASCIIFolding converts letters, numbers and symbols to their ascii
+// equivalent when one exists, like "café" to "cafe" and "straße" to "strasse"
+func ASCIIFolding(tokens []string) ([]string, error) {
+	asciiFolder := asciiFolders.Get().(transform.Transformer)
+	defer asciiFolders.Put(asciiFolder)
+
+	out := make([]string, len(tokens))
+	for i, token := range tokens {
+		folded, _, err := transform.String(asciiFolder, token)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = asciiReplacer.Replace(folded)
+	}
+	return out, nil
+}
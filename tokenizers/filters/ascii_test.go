@@ -0,0 +1,47 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestASCIIFolding(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		result []string
+	}{
+		{
+			name:   "accents",
+			tokens: []string{"café", "naïve", "Ångström"},
+			result: []string{"cafe", "naive", "Angstrom"},
+		},
+		{
+			name:   "special letters",
+			tokens: []string{"straße", "Æsir", "Øresund", "Łódź"},
+			result: []string{"strasse", "AEsir", "Oresund", "Lodz"},
+		},
+		{
+			name:   "punctuation",
+			tokens: []string{"it’s", "a–b"},
+			result: []string{"it's", "a-b"},
+		},
+		{
+			name:   "no ascii equivalent",
+			tokens: []string{"日本"},
+			result: []string{"日本"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ASCIIFolding(test.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("Expected %v, got %v", test.result, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package filters
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WordDelimiter splits tokens into their parts on punctuation, case
+// changes and letter/number changes. "wi-fi" becomes "wi" and "fi",
+// "camelCase" becomes "camel" and "Case", and "PowerShot500"
+// becomes "Power", "Shot" and "500"
+func WordDelimiter(tokens []string) ([]string, error) {
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		out = append(out, wordParts(token)...)
+	}
+	return out, nil
+}
+
+// WordDelimiterCatenate does the same as WordDelimiter but also adds
+// all the parts joined together, so "wi-fi" is searchable as "wifi"
+func WordDelimiterCatenate(tokens []string) ([]string, error) {
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		parts := wordParts(token)
+		out = append(out, parts...)
+		if len(parts) > 1 {
+			out = append(out, strings.Join(parts, ""))
+		}
+	}
+	return out, nil
+}
+
+// wordParts splits a single token into its parts
+func wordParts(token string) []string {
+	var parts []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, string(current))
+		}
+		current = nil
+	}
+
+	chars := []rune(token)
+	for i, r := range chars {
+		// Anything that is not a letter or number is a delimiter
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+			flush()
+			continue
+		}
+
+		if len(current) > 0 {
+			prev := current[len(current)-1]
+			switch {
+			case unicode.IsNumber(prev) != unicode.IsNumber(r):
+				// "Shot500" splits between letters and numbers
+				flush()
+			case unicode.IsLower(prev) && unicode.IsUpper(r):
+				// "camelCase" splits before the upper case letter
+				flush()
+			case unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(chars) && unicode.IsLower(chars[i+1]):
+				// "HTTPServer" splits before the last upper case letter
+				flush()
+			}
+		}
+
+		current = append(current, r)
+	}
+	flush()
+
+	return parts
+}
@@ -0,0 +1,64 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordDelimiter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Func
+		tokens []string
+		result []string
+	}{
+		{
+			name:   "hyphen",
+			filter: WordDelimiter,
+			tokens: []string{"wi-fi"},
+			result: []string{"wi", "fi"},
+		},
+		{
+			name:   "camel case",
+			filter: WordDelimiter,
+			tokens: []string{"camelCase"},
+			result: []string{"camel", "Case"},
+		},
+		{
+			name:   "letters and numbers",
+			filter: WordDelimiter,
+			tokens: []string{"PowerShot500"},
+			result: []string{"Power", "Shot", "500"},
+		},
+		{
+			name:   "acronym",
+			filter: WordDelimiter,
+			tokens: []string{"HTTPServer"},
+			result: []string{"HTTP", "Server"},
+		},
+		{
+			name:   "plain",
+			filter: WordDelimiter,
+			tokens: []string{"hello"},
+			result: []string{"hello"},
+		},
+		{
+			name:   "catenate",
+			filter: WordDelimiterCatenate,
+			tokens: []string{"wi-fi", "hello"},
+			result: []string{"wi", "fi", "wifi", "hello"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.filter(test.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("Expected %v, got %v", test.result, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package filters
+
+import (
+	"strings"
+)
+
+// frenchArticles are the default elided articles
+var frenchArticles = []string{"l", "m", "t", "qu", "n", "s", "j", "d", "c", "jusqu", "quoiqu", "lorsqu", "puisqu"}
+
+// Elision removes french elided articles from the start
+// of tokens, so "l'avion" becomes "avion"
+func Elision(tokens []string) ([]string, error) {
+	return elide(tokens, frenchArticles), nil
+}
+
+// ElisionArticles returns a filter that removes the given
+// elided articles, like "dell" for italian "dell'anno"
+func ElisionArticles(articles ...string) Func {
+	return func(tokens []string) ([]string, error) {
+		return elide(tokens, articles), nil
+	}
+}
+
+func elide(tokens []string, articles []string) []string {
+	out := make([]string, len(tokens))
+	for i, token := range tokens {
+		out[i] = token
+
+		index := strings.IndexAny(token, "'’")
+		if index <= 0 {
+			continue
+		}
+
+		prefix := token[:index]
+		for _, article := range articles {
+			if strings.EqualFold(prefix, article) {
+				// Skip past the apostrophe which can be more than one byte
+				rest := strings.TrimLeft(token[index:], "'’")
+				if rest != "" {
+					out[i] = rest
+				}
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestElision(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Func
+		tokens []string
+		result []string
+	}{
+		{
+			name:   "french",
+			filter: Elision,
+			tokens: []string{"l'avion", "d’eau", "qu'il", "avion"},
+			result: []string{"avion", "eau", "il", "avion"},
+		},
+		{
+			name:   "not an article",
+			filter: Elision,
+			tokens: []string{"rock'n'roll"},
+			result: []string{"rock'n'roll"},
+		},
+		{
+			name:   "custom articles",
+			filter: ElisionArticles("dell", "all"),
+			tokens: []string{"dell'anno", "all'una", "l'avion"},
+			result: []string{"anno", "una", "l'avion"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.filter(test.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("Expected %v, got %v", test.result, result)
+			}
+		})
+	}
+}
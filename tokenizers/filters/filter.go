@@ -3,25 +3,50 @@ package filters
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"reflect"
 	"runtime"
 	"strings"
+	"unicode/utf8"
 )
 
 type Func func([]string) ([]string, error)
 
+// FuncsID returns a short id for the filters made from their function
+// names. Configured filters, like Length(2, 10), are closures that share
+// the name of their constructor, use FilterID and HashIDs for those
 func FuncsID(filters ...Func) string {
+	ids := make([]string, len(filters))
+	for i, filter := range filters {
+		ids[i] = FilterID(filter)
+	}
+
+	return HashIDs(ids...)
+}
+
+// FilterID returns the function name of the filter followed by the
+// parameters it was configured with, if any, so FilterID(Length(2, 10),
+// 2, 10) and FilterID(Length(3, 10), 3, 10) are different
+func FilterID(filter Func, config ...any) string {
+	funcPtr := reflect.ValueOf(filter).Pointer()
+	funcName := runtime.FuncForPC(funcPtr).Name()
+
+	if len(config) == 0 {
+		return funcName
+	}
+	return funcName + fmt.Sprintf("%v", config)
+}
+
+// HashIDs returns a short id for the filter ids, in order
+func HashIDs(ids ...string) string {
 	// if no filters, return empty string
-	if len(filters) == 0 {
+	if len(ids) == 0 {
 		return ""
 	}
 
 	identifierStr := ""
-	for _, filter := range filters {
-		funcPtr := reflect.ValueOf(filter).Pointer()
-		funcName := runtime.FuncForPC(funcPtr).Name()
-
-		identifierStr += funcName + ";"
+	for _, id := range ids {
+		identifierStr += id + ";"
 	}
 
 	// Use SHA-256 and then truncate.
@@ -43,6 +68,49 @@ func Lowercase(tokens []string) ([]string, error) {
 	return out, nil
 }
 
+// Trim removes leading and trailing whitespace
+// from tokens and drops tokens that end up empty
+func Trim(tokens []string) ([]string, error) {
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token != "" {
+			out = append(out, token)
+		}
+	}
+	return out, nil
+}
+
+// Unique removes duplicate tokens keeping the first occurrence
+func Unique(tokens []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(tokens))
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		out = append(out, token)
+	}
+	return out, nil
+}
+
+// Length returns a filter that removes tokens with fewer
+// than min or more than max characters. A max of 0 is unlimited
+func Length(min, max int) Func {
+	return func(tokens []string) ([]string, error) {
+		out := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			length := utf8.RuneCountInString(token)
+			if length < min || (max > 0 && length > max) {
+				continue
+			}
+			out = append(out, token)
+		}
+		return out, nil
+	}
+}
+
 var stopwords = map[string]struct{}{
 	"a": {}, "about": {}, "above": {}, "after": {}, "again": {}, "against": {}, "all": {},
 	"am": {}, "an": {}, "and": {}, "any": {}, "are": {}, "arent": {}, "as": {}, "at": {},
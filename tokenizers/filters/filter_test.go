@@ -1,6 +1,9 @@
 package filters
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestFuncsID(t *testing.T) {
 	tests := []struct {
@@ -16,17 +19,17 @@ func TestFuncsID(t *testing.T) {
 		{
 			name:   "single",
 			funcs:  []Func{Lowercase},
-			result: "ecf35cbbd166",
+			result: "f267b46b5278",
 		},
 		{
 			name:   "multiple",
 			funcs:  []Func{Lowercase, RemoveStopwords},
-			result: "76040804a901",
+			result: "67cae8b288a7",
 		},
 		{
 			name:   "multiple_reverse",
 			funcs:  []Func{RemoveStopwords, Lowercase},
-			result: "007bdd218ee4", // Should be different from multiple
+			result: "5d638b180487", // Should be different from multiple
 		},
 	}
 
@@ -85,3 +88,69 @@ func TestLowercaseFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestFuncsIDConfigured(t *testing.T) {
+	// Filters from the same constructor with different parameters should differ
+	if HashIDs(FilterID(Length(1, 5), 1, 5)) == HashIDs(FilterID(Length(2, 5), 2, 5)) {
+		t.Errorf("Expected different ids for different lengths")
+	}
+
+	// The same parameters should result in the same id
+	if HashIDs(FilterID(Length(1, 5), 1, 5)) != HashIDs(FilterID(Length(1, 5), 1, 5)) {
+		t.Errorf("Expected the same id for the same lengths")
+	}
+
+	// Filters without parameters have the same id as FuncsID gives them
+	if HashIDs(FilterID(Lowercase), FilterID(Shingles(2, 3), 2, 3)) == FuncsID(Lowercase, Shingles(2, 3)) {
+		t.Errorf("Expected the shingle parameters to change the id")
+	}
+	if HashIDs(FilterID(Lowercase), FilterID(RemoveStopwords)) != FuncsID(Lowercase, RemoveStopwords) {
+		t.Errorf("Expected the same id as FuncsID")
+	}
+}
+
+func TestBasicFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Func
+		tokens []string
+		result []string
+	}{
+		{
+			name:   "trim",
+			filter: Trim,
+			tokens: []string{" hello ", "\tworld", "  "},
+			result: []string{"hello", "world"},
+		},
+		{
+			name:   "unique",
+			filter: Unique,
+			tokens: []string{"a", "b", "a", "c", "b"},
+			result: []string{"a", "b", "c"},
+		},
+		{
+			name:   "length",
+			filter: Length(2, 4),
+			tokens: []string{"a", "ab", "abcd", "abcde", "日本"},
+			result: []string{"ab", "abcd", "日本"},
+		},
+		{
+			name:   "length no max",
+			filter: Length(3, 0),
+			tokens: []string{"ab", "abcdefghij"},
+			result: []string{"abcdefghij"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.filter(test.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("Expected %v, got %v", test.result, result)
+			}
+		})
+	}
+}
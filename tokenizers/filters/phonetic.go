@@ -0,0 +1,635 @@
+package filters
+
+import (
+	"strings"
+)
+
+// Soundex replaces each token with its american soundex code
+func Soundex(tokens []string) ([]string, error) {
+	return phonetic(tokens, soundexCodes, false), nil
+}
+
+// SoundexWithOriginal keeps each token and adds its soundex code after it.
+// Use it when indexing and Soundex on the search so exact matches still match
+func SoundexWithOriginal(tokens []string) ([]string, error) {
+	return phonetic(tokens, soundexCodes, true), nil
+}
+
+// RefinedSoundex replaces each token with its refined soundex code
+func RefinedSoundex(tokens []string) ([]string, error) {
+	return phonetic(tokens, refinedSoundexCodes, false), nil
+}
+
+// RefinedSoundexWithOriginal keeps each token and adds its refined soundex code after it
+func RefinedSoundexWithOriginal(tokens []string) ([]string, error) {
+	return phonetic(tokens, refinedSoundexCodes, true), nil
+}
+
+// DoubleMetaphone replaces each token with its primary double
+// metaphone code and its alternate code when they are different
+func DoubleMetaphone(tokens []string) ([]string, error) {
+	return phonetic(tokens, doubleMetaphoneCodes, false), nil
+}
+
+// DoubleMetaphoneWithOriginal keeps each token and adds its double metaphone codes after it
+func DoubleMetaphoneWithOriginal(tokens []string) ([]string, error) {
+	return phonetic(tokens, doubleMetaphoneCodes, true), nil
+}
+
+// phonetic runs the encoder on each token. Tokens that do not
+// encode to anything, like numbers, are passed through as is
+func phonetic(tokens []string, encode func(string) []string, keepOriginal bool) []string {
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		codes := encode(token)
+		if keepOriginal || len(codes) == 0 {
+			out = append(out, token)
+		}
+		for _, code := range codes {
+			if keepOriginal && code == token {
+				continue
+			}
+			out = append(out, code)
+		}
+	}
+	return out
+}
+
+// asciiLetters returns the uppercased ascii letters in the token
+func asciiLetters(token string) []byte {
+	var letters []byte
+	for _, r := range strings.ToUpper(token) {
+		if r >= 'A' && r <= 'Z' {
+			letters = append(letters, byte(r))
+		}
+	}
+	return letters
+}
+
+// Letter codes for A through Z
+const (
+	soundexMapping        = "01230120022455012623010202"
+	refinedSoundexMapping = "01360240043788015936020505"
+)
+
+func soundexCodes(token string) []string {
+	letters := asciiLetters(token)
+	if len(letters) == 0 {
+		return nil
+	}
+
+	code := []byte{letters[0]}
+	last := soundexMapping[letters[0]-'A']
+	for _, letter := range letters[1:] {
+		digit := soundexMapping[letter-'A']
+
+		switch {
+		case letter == 'H' || letter == 'W':
+			// H and W do not separate letters with the same code
+			continue
+		case digit == '0':
+			// Vowels separate letters with the same code
+			last = digit
+			continue
+		case digit != last:
+			code = append(code, digit)
+		}
+		last = digit
+
+		if len(code) == 4 {
+			break
+		}
+	}
+
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+
+	return []string{string(code)}
+}
+
+func refinedSoundexCodes(token string) []string {
+	letters := asciiLetters(token)
+	if len(letters) == 0 {
+		return nil
+	}
+
+	code := []byte{letters[0]}
+	last := byte('*')
+	for _, letter := range letters {
+		digit := refinedSoundexMapping[letter-'A']
+		if digit == last {
+			continue
+		}
+		code = append(code, digit)
+		last = digit
+	}
+
+	return []string{string(code)}
+}
+
+func doubleMetaphoneCodes(token string) []string {
+	primary, alternate := doubleMetaphone(token, 4)
+	if primary == "" {
+		return nil
+	}
+	if alternate == "" || alternate == primary {
+		return []string{primary}
+	}
+	return []string{primary, alternate}
+}
+
+// metaphoneResult builds the primary and
+// alternate codes up to a maximum length
+type metaphoneResult struct {
+	primary   []rune
+	alternate []rune
+	max       int
+}
+
+func (m *metaphoneResult) append(primary, alternate string) {
+	m.appendPrimary(primary)
+	m.appendAlternate(alternate)
+}
+
+func (m *metaphoneResult) appendPrimary(val string) {
+	for _, r := range val {
+		if len(m.primary) < m.max {
+			m.primary = append(m.primary, r)
+		}
+	}
+}
+
+func (m *metaphoneResult) appendAlternate(val string) {
+	for _, r := range val {
+		if len(m.alternate) < m.max {
+			m.alternate = append(m.alternate, r)
+		}
+	}
+}
+
+func (m *metaphoneResult) complete() bool {
+	return len(m.primary) >= m.max && len(m.alternate) >= m.max
+}
+
+// metaphoneWord is the uppercased word being encoded
+type metaphoneWord []rune
+
+func (w metaphoneWord) at(i int) rune {
+	if i < 0 || i >= len(w) {
+		return 0
+	}
+	return w[i]
+}
+
+// contains checks if the length runes starting at start match any of the criteria
+func (w metaphoneWord) contains(start, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(w) {
+		return false
+	}
+
+	target := string(w[start : start+length])
+	for _, c := range criteria {
+		if target == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (w metaphoneWord) vowel(i int) bool {
+	return strings.ContainsRune("AEIOUY", w.at(i))
+}
+
+// doubleMetaphone is a port of Lawrence Philips' double metaphone algorithm
+func doubleMetaphone(token string, max int) (string, string) {
+	w := metaphoneWord(strings.ToUpper(strings.TrimSpace(token)))
+	if len(w) == 0 {
+		return "", ""
+	}
+
+	str := string(w)
+	slavoGermanic := strings.ContainsAny(str, "WK") || strings.Contains(str, "CZ") || strings.Contains(str, "WITZ")
+	last := len(w) - 1
+
+	m := &metaphoneResult{max: max}
+
+	i := 0
+	if w.contains(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	}
+
+	for !m.complete() && i <= last {
+		switch w.at(i) {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if i == 0 {
+				m.append("A", "A")
+			}
+			i++
+		case 'B':
+			m.append("P", "P")
+			i += skip(w.at(i+1) == 'B')
+		case 'Ç':
+			m.append("S", "S")
+			i++
+		case 'C':
+			i = metaphoneC(w, m, i)
+		case 'D':
+			switch {
+			case w.contains(i, 2, "DG"):
+				if w.contains(i+2, 1, "I", "E", "Y") {
+					m.append("J", "J")
+					i += 3
+				} else {
+					m.append("TK", "TK")
+					i += 2
+				}
+			case w.contains(i, 2, "DT", "DD"):
+				m.append("T", "T")
+				i += 2
+			default:
+				m.append("T", "T")
+				i++
+			}
+		case 'F':
+			m.append("F", "F")
+			i += skip(w.at(i+1) == 'F')
+		case 'G':
+			i = metaphoneG(w, m, i, slavoGermanic)
+		case 'H':
+			if (i == 0 || w.vowel(i-1)) && w.vowel(i+1) {
+				m.append("H", "H")
+				i += 2
+			} else {
+				i++
+			}
+		case 'J':
+			i = metaphoneJ(w, m, i, slavoGermanic)
+		case 'K':
+			m.append("K", "K")
+			i += skip(w.at(i+1) == 'K')
+		case 'L':
+			if w.at(i+1) == 'L' {
+				if (i == last-2 && w.contains(i-1, 4, "ILLO", "ILLA", "ALLE")) ||
+					((w.contains(last-1, 2, "AS", "OS") || w.contains(last, 1, "A", "O")) && w.contains(i-1, 4, "ALLE")) {
+					m.appendPrimary("L")
+				} else {
+					m.append("L", "L")
+				}
+				i += 2
+			} else {
+				m.append("L", "L")
+				i++
+			}
+		case 'M':
+			m.append("M", "M")
+			i += skip(w.at(i+1) == 'M' || (w.contains(i-1, 3, "UMB") && (i+1 == last || w.contains(i+2, 2, "ER"))))
+		case 'N':
+			m.append("N", "N")
+			i += skip(w.at(i+1) == 'N')
+		case 'Ñ':
+			m.append("N", "N")
+			i++
+		case 'P':
+			if w.at(i+1) == 'H' {
+				m.append("F", "F")
+				i += 2
+			} else {
+				m.append("P", "P")
+				i += skip(w.contains(i+1, 1, "P", "B"))
+			}
+		case 'Q':
+			m.append("K", "K")
+			i += skip(w.at(i+1) == 'Q')
+		case 'R':
+			if i == last && !slavoGermanic && w.contains(i-2, 2, "IE") && !w.contains(i-4, 2, "ME", "MA") {
+				m.appendAlternate("R")
+			} else {
+				m.append("R", "R")
+			}
+			i += skip(w.at(i+1) == 'R')
+		case 'S':
+			i = metaphoneS(w, m, i, slavoGermanic)
+		case 'T':
+			switch {
+			case w.contains(i, 4, "TION"), w.contains(i, 3, "TIA", "TCH"):
+				m.append("X", "X")
+				i += 3
+			case w.contains(i, 2, "TH"), w.contains(i, 3, "TTH"):
+				if w.contains(i+2, 2, "OM", "AM") || w.contains(0, 4, "VAN ", "VON ") || w.contains(0, 3, "SCH") {
+					m.append("T", "T")
+				} else {
+					m.append("0", "T")
+				}
+				i += 2
+			default:
+				m.append("T", "T")
+				i += skip(w.contains(i+1, 1, "T", "D"))
+			}
+		case 'V':
+			m.append("F", "F")
+			i += skip(w.at(i+1) == 'V')
+		case 'W':
+			i = metaphoneW(w, m, i)
+		case 'X':
+			if i == 0 {
+				m.append("S", "S")
+				i++
+			} else {
+				if !(i == last && (w.contains(i-3, 3, "IAU", "EAU") || w.contains(i-2, 2, "AU", "OU"))) {
+					m.append("KS", "KS")
+				}
+				i += skip(w.contains(i+1, 1, "C", "X"))
+			}
+		case 'Z':
+			if w.at(i+1) == 'H' {
+				m.append("J", "J")
+				i += 2
+			} else {
+				if w.contains(i+1, 2, "ZO", "ZI", "ZA") || (slavoGermanic && i > 0 && w.at(i-1) != 'T') {
+					m.append("S", "TS")
+				} else {
+					m.append("S", "S")
+				}
+				i += skip(w.at(i+1) == 'Z')
+			}
+		default:
+			i++
+		}
+	}
+
+	return string(m.primary), string(m.alternate)
+}
+
+// skip returns how far to move forward, skipping
+// the next letter when it is a duplicate sound
+func skip(double bool) int {
+	if double {
+		return 2
+	}
+	return 1
+}
+
+func metaphoneC(w metaphoneWord, m *metaphoneResult, i int) int {
+	switch {
+	case metaphoneCGermanic(w, i):
+		m.append("K", "K")
+		return i + 2
+	case i == 0 && w.contains(i, 6, "CAESAR"):
+		m.append("S", "S")
+		return i + 2
+	case w.contains(i, 2, "CH"):
+		return metaphoneCH(w, m, i)
+	case w.contains(i, 2, "CZ") && !w.contains(i-2, 4, "WICZ"):
+		m.append("S", "X")
+		return i + 2
+	case w.contains(i+1, 3, "CIA"):
+		m.append("X", "X")
+		return i + 3
+	case w.contains(i, 2, "CC") && !(i == 1 && w.at(0) == 'M'):
+		if w.contains(i+2, 1, "I", "E", "H") && !w.contains(i+2, 2, "HU") {
+			if (i == 1 && w.at(i-1) == 'A') || w.contains(i-1, 5, "UCCEE", "UCCES") {
+				m.append("KS", "KS")
+			} else {
+				m.append("X", "X")
+			}
+			return i + 3
+		}
+		m.append("K", "K")
+		return i + 2
+	case w.contains(i, 2, "CK", "CG", "CQ"):
+		m.append("K", "K")
+		return i + 2
+	case w.contains(i, 2, "CI", "CE", "CY"):
+		if w.contains(i, 3, "CIO", "CIE", "CIA") {
+			m.append("S", "X")
+		} else {
+			m.append("S", "S")
+		}
+		return i + 2
+	}
+
+	m.append("K", "K")
+	switch {
+	case w.contains(i+1, 2, " C", " Q", " G"):
+		return i + 3
+	case w.contains(i+1, 1, "C", "K", "Q") && !w.contains(i+1, 2, "CE", "CI"):
+		return i + 2
+	}
+	return i + 1
+}
+
+// metaphoneCGermanic checks for germanic "ach" like in "bacher"
+func metaphoneCGermanic(w metaphoneWord, i int) bool {
+	switch {
+	case w.contains(i, 4, "CHIA"):
+		return true
+	case i <= 1, w.vowel(i - 2), !w.contains(i-1, 3, "ACH"):
+		return false
+	}
+
+	c := w.at(i + 2)
+	return (c != 'I' && c != 'E') || w.contains(i-2, 6, "BACHER", "MACHER")
+}
+
+func metaphoneCH(w metaphoneWord, m *metaphoneResult, i int) int {
+	switch {
+	case i > 0 && w.contains(i, 4, "CHAE"):
+		m.append("K", "X")
+		return i + 2
+	case i == 0 && (w.contains(i+1, 5, "HARAC", "HARIS") || w.contains(i+1, 3, "HOR", "HYM", "HIA", "HEM")) &&
+		!w.contains(0, 5, "CHORE"):
+		// Greek roots like "chemistry" and "chorus"
+		m.append("K", "K")
+		return i + 2
+	case w.contains(0, 4, "VAN ", "VON ") || w.contains(0, 3, "SCH") ||
+		w.contains(i-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		w.contains(i+2, 1, "T", "S") ||
+		((w.contains(i-1, 1, "A", "O", "U", "E") || i == 0) &&
+			(w.contains(i+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || i+1 == len(w)-1)):
+		// Germanic roots
+		m.append("K", "K")
+		return i + 2
+	case i > 0:
+		if w.contains(0, 2, "MC") {
+			m.append("K", "K")
+		} else {
+			m.append("X", "K")
+		}
+		return i + 2
+	}
+
+	m.append("X", "X")
+	return i + 2
+}
+
+func metaphoneG(w metaphoneWord, m *metaphoneResult, i int, slavoGermanic bool) int {
+	switch {
+	case w.at(i+1) == 'H':
+		return metaphoneGH(w, m, i)
+	case w.at(i+1) == 'N':
+		switch {
+		case i == 1 && w.vowel(0) && !slavoGermanic:
+			m.append("KN", "N")
+		case !w.contains(i+2, 2, "EY") && w.at(i+1) != 'Y' && !slavoGermanic:
+			m.append("N", "KN")
+		default:
+			m.append("KN", "KN")
+		}
+		return i + 2
+	case w.contains(i+1, 2, "LI") && !slavoGermanic:
+		m.append("KL", "L")
+		return i + 2
+	case i == 0 && (w.at(i+1) == 'Y' || w.contains(i+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.append("K", "J")
+		return i + 2
+	case (w.contains(i+1, 2, "ER") || w.at(i+1) == 'Y') &&
+		!w.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!w.contains(i-1, 1, "E", "I") &&
+		!w.contains(i-1, 3, "RGY", "OGY"):
+		m.append("K", "J")
+		return i + 2
+	case w.contains(i+1, 1, "E", "I", "Y") || w.contains(i-1, 4, "AGGI", "OGGI"):
+		switch {
+		case w.contains(0, 4, "VAN ", "VON ") || w.contains(0, 3, "SCH") || w.contains(i+1, 2, "ET"):
+			m.append("K", "K")
+		case w.contains(i+1, 3, "IER"):
+			m.append("J", "J")
+		default:
+			m.append("J", "K")
+		}
+		return i + 2
+	case w.at(i+1) == 'G':
+		m.append("K", "K")
+		return i + 2
+	}
+
+	m.append("K", "K")
+	return i + 1
+}
+
+func metaphoneGH(w metaphoneWord, m *metaphoneResult, i int) int {
+	switch {
+	case i > 0 && !w.vowel(i-1):
+		m.append("K", "K")
+	case i == 0:
+		if w.at(i+2) == 'I' {
+			m.append("J", "J")
+		} else {
+			m.append("K", "K")
+		}
+	case (i > 1 && w.contains(i-2, 1, "B", "H", "D")) ||
+		(i > 2 && w.contains(i-3, 1, "B", "H", "D")) ||
+		(i > 3 && w.contains(i-4, 1, "B", "H")):
+		// Silent like in "hugh" and "bough"
+	case i > 2 && w.at(i-1) == 'U' && w.contains(i-3, 1, "C", "G", "L", "R", "T"):
+		// Sounds like f in "laugh" and "tough"
+		m.append("F", "F")
+	case i > 0 && w.at(i-1) != 'I':
+		m.append("K", "K")
+	}
+	return i + 2
+}
+
+func metaphoneJ(w metaphoneWord, m *metaphoneResult, i int, slavoGermanic bool) int {
+	if w.contains(i, 4, "JOSE") || w.contains(0, 4, "SAN ") {
+		if (i == 0 && w.at(i+4) == ' ') || len(w) == 4 || w.contains(0, 4, "SAN ") {
+			m.append("H", "H")
+		} else {
+			m.append("J", "H")
+		}
+		return i + 1
+	}
+
+	switch {
+	case i == 0:
+		m.append("J", "A")
+	case w.vowel(i-1) && !slavoGermanic && (w.at(i+1) == 'A' || w.at(i+1) == 'O'):
+		m.append("J", "H")
+	case i == len(w)-1:
+		m.appendPrimary("J")
+	case !w.contains(i+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !w.contains(i-1, 1, "S", "K", "L"):
+		m.append("J", "J")
+	}
+
+	return i + skip(w.at(i+1) == 'J')
+}
+
+func metaphoneS(w metaphoneWord, m *metaphoneResult, i int, slavoGermanic bool) int {
+	switch {
+	case w.contains(i-1, 3, "ISL", "YSL"):
+		// Silent like in "island" and "carlisle"
+		return i + 1
+	case i == 0 && w.contains(i, 5, "SUGAR"):
+		m.append("X", "S")
+		return i + 1
+	case w.contains(i, 2, "SH"):
+		if w.contains(i+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.append("S", "S")
+		} else {
+			m.append("X", "X")
+		}
+		return i + 2
+	case w.contains(i, 3, "SIO", "SIA") || w.contains(i, 4, "SIAN"):
+		if slavoGermanic {
+			m.append("S", "S")
+		} else {
+			m.append("S", "X")
+		}
+		return i + 3
+	case (i == 0 && w.contains(i+1, 1, "M", "N", "L", "W")) || w.contains(i+1, 1, "Z"):
+		m.append("S", "X")
+		return i + skip(w.contains(i+1, 1, "Z"))
+	case w.contains(i, 2, "SC"):
+		switch {
+		case w.at(i+2) == 'H':
+			switch {
+			case w.contains(i+3, 2, "ER", "EN"):
+				m.append("X", "SK")
+			case w.contains(i+3, 2, "OO", "UY", "ED", "EM"):
+				m.append("SK", "SK")
+			case i == 0 && !w.vowel(3) && w.at(3) != 'W':
+				m.append("X", "S")
+			default:
+				m.append("X", "X")
+			}
+		case w.contains(i+2, 1, "I", "E", "Y"):
+			m.append("S", "S")
+		default:
+			m.append("SK", "SK")
+		}
+		return i + 3
+	}
+
+	// French endings like in "resnais" and "artois"
+	if i == len(w)-1 && w.contains(i-2, 2, "AI", "OI") {
+		m.appendAlternate("S")
+	} else {
+		m.append("S", "S")
+	}
+	return i + skip(w.contains(i+1, 1, "S", "Z"))
+}
+
+func metaphoneW(w metaphoneWord, m *metaphoneResult, i int) int {
+	switch {
+	case w.contains(i, 2, "WR"):
+		m.append("R", "R")
+		return i + 2
+	case i == 0 && (w.vowel(i+1) || w.contains(i, 2, "WH")):
+		if w.vowel(i + 1) {
+			m.append("A", "F")
+		} else {
+			m.append("A", "A")
+		}
+		return i + 1
+	case (i == len(w)-1 && w.vowel(i-1)) ||
+		w.contains(i-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		w.contains(0, 3, "SCH"):
+		m.appendAlternate("F")
+		return i + 1
+	case w.contains(i, 4, "WICZ", "WITZ"):
+		m.append("TS", "FX")
+		return i + 4
+	}
+	return i + 1
+}
@@ -0,0 +1,139 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSoundex(t *testing.T) {
+	tests := []struct {
+		token string
+		code  string
+	}{
+		{"Robert", "R163"},
+		{"Rupert", "R163"},
+		{"Rubin", "R150"},
+		{"Ashcraft", "A261"},
+		{"Tymczak", "T522"},
+		{"Pfister", "P236"},
+		{"Smith", "S530"},
+		{"Smyth", "S530"},
+		{"Lee", "L000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			result, err := Soundex([]string{tt.token})
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if len(result) != 1 || result[0] != tt.code {
+				t.Errorf("Expected %v, got %v", tt.code, result)
+			}
+		})
+	}
+}
+
+func TestRefinedSoundex(t *testing.T) {
+	tests := []struct {
+		token string
+		code  string
+	}{
+		{"testing", "T6036084"},
+		{"The", "T60"},
+		{"quick", "Q503"},
+		{"brown", "B1908"},
+		{"fox", "F205"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			result, err := RefinedSoundex([]string{tt.token})
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if len(result) != 1 || result[0] != tt.code {
+				t.Errorf("Expected %v, got %v", tt.code, result)
+			}
+		})
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	tests := []struct {
+		token string
+		codes []string
+	}{
+		{"Smith", []string{"SM0", "XMT"}},
+		{"Smyth", []string{"SM0", "XMT"}},
+		{"Schmidt", []string{"XMT", "SMT"}},
+		{"Katherine", []string{"K0RN", "KTRN"}},
+		{"Catherine", []string{"K0RN", "KTRN"}},
+		{"Thompson", []string{"TMPS"}},
+		{"Jose", []string{"HS"}},
+		{"Xavier", []string{"SF", "SFR"}},
+		{"knight", []string{"NT"}},
+		{"laugh", []string{"LF"}},
+		{"Gallegos", []string{"KLKS", "KKS"}},
+		{"Wright", []string{"RT"}},
+		{"Czerny", []string{"SRN", "XRN"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			result, err := DoubleMetaphone([]string{tt.token})
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.codes) {
+				t.Errorf("Expected %v, got %v", tt.codes, result)
+			}
+		})
+	}
+}
+
+func TestPhoneticWithOriginal(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Func
+		tokens []string
+		result []string
+	}{
+		{
+			name:   "soundex",
+			filter: SoundexWithOriginal,
+			tokens: []string{"smyth", "jones"},
+			result: []string{"smyth", "S530", "jones", "J520"},
+		},
+		{
+			name:   "refined soundex",
+			filter: RefinedSoundexWithOriginal,
+			tokens: []string{"fox"},
+			result: []string{"fox", "F205"},
+		},
+		{
+			name:   "double metaphone",
+			filter: DoubleMetaphoneWithOriginal,
+			tokens: []string{"katherine"},
+			result: []string{"katherine", "K0RN", "KTRN"},
+		},
+		{
+			name:   "numbers pass through",
+			filter: DoubleMetaphone,
+			tokens: []string{"123"},
+			result: []string{"123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.filter(tt.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.result) {
+				t.Errorf("Expected %v, got %v", tt.result, result)
+			}
+		})
+	}
+}
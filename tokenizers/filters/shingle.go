@@ -0,0 +1,29 @@
+package filters
+
+import (
+	"strings"
+)
+
+// Shingles returns a filter that adds word n-grams between min and max
+// words long after each token, so "new york city" with 2, 2 adds
+// "new york" and "york city" for phrase like matching
+func Shingles(min, max int) Func {
+	// Single words are already the tokens themselves
+	if min < 2 {
+		min = 2
+	}
+	if max < min {
+		max = min
+	}
+
+	return func(tokens []string) ([]string, error) {
+		out := make([]string, 0, len(tokens)*(max-min+2))
+		for i, token := range tokens {
+			out = append(out, token)
+			for size := min; size <= max && i+size <= len(tokens); size++ {
+				out = append(out, strings.Join(tokens[i:i+size], " "))
+			}
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,46 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShingles(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Func
+		tokens []string
+		result []string
+	}{
+		{
+			name:   "bigrams",
+			filter: Shingles(2, 2),
+			tokens: []string{"new", "york", "city"},
+			result: []string{"new", "new york", "york", "york city", "city"},
+		},
+		{
+			name:   "bigrams and trigrams",
+			filter: Shingles(2, 3),
+			tokens: []string{"new", "york", "city"},
+			result: []string{"new", "new york", "new york city", "york", "york city", "city"},
+		},
+		{
+			name:   "single token",
+			filter: Shingles(2, 2),
+			tokens: []string{"hello"},
+			result: []string{"hello"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.filter(test.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("Expected %v, got %v", test.result, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package filters
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// languageStopwords are the built in stopword lists by language
+var languageStopwords = map[string][]string{
+	"french": {
+		"au", "aux", "avec", "ce", "ces", "dans", "de", "des", "du", "elle", "en", "et", "eux",
+		"il", "ils", "je", "la", "le", "les", "leur", "lui", "ma", "mais", "me", "meme", "mes",
+		"moi", "mon", "ne", "nos", "notre", "nous", "on", "ou", "par", "pas", "pour", "qu",
+		"que", "qui", "sa", "se", "ses", "son", "sur", "ta", "te", "tes", "toi", "ton", "tu",
+		"un", "une", "vos", "votre", "vous", "c", "d", "j", "l", "m", "n", "s", "t", "y",
+		"est", "sont", "etait", "ete", "etre", "avoir", "a", "ont",
+	},
+	"german": {
+		"aber", "alle", "als", "also", "am", "an", "auch", "auf", "aus", "bei", "bin", "bis",
+		"da", "das", "dass", "dem", "den", "der", "des", "die", "doch", "du", "ein", "eine",
+		"einem", "einen", "einer", "er", "es", "fur", "hat", "ich", "ihr", "im", "in", "ist",
+		"ja", "kein", "mit", "nach", "nicht", "noch", "nur", "oder", "sich", "sie", "sind",
+		"so", "uber", "um", "und", "uns", "von", "vor", "war", "was", "wie", "wir", "zu", "zum", "zur",
+	},
+	"spanish": {
+		"a", "al", "algo", "como", "con", "de", "del", "e", "el", "ella", "ellos", "en", "entre",
+		"era", "es", "esta", "este", "esto", "fue", "ha", "la", "las", "le", "les", "lo", "los",
+		"mas", "me", "mi", "muy", "no", "nos", "o", "para", "pero", "por", "que", "se", "si",
+		"sin", "sobre", "su", "sus", "te", "tu", "un", "una", "uno", "y", "ya", "yo",
+	},
+	"italian": {
+		"a", "ad", "al", "alla", "anche", "che", "chi", "con", "da", "dal", "dei", "del", "della",
+		"di", "e", "ed", "gli", "ha", "i", "il", "in", "io", "la", "le", "lo", "ma", "mi", "ne",
+		"nel", "nella", "non", "o", "per", "piu", "quella", "questo", "se", "si", "sono", "su",
+		"sua", "suo", "tra", "tu", "un", "una", "uno",
+	},
+	"portuguese": {
+		"a", "ao", "aos", "as", "com", "como", "da", "das", "de", "do", "dos", "e", "ela", "ele",
+		"em", "entre", "era", "essa", "esse", "esta", "este", "eu", "foi", "ha", "isso", "ja",
+		"la", "mais", "mas", "me", "na", "nao", "nas", "no", "nos", "o", "os", "ou", "para",
+		"pela", "pelo", "por", "que", "se", "sem", "seu", "sua", "um", "uma",
+	},
+	"dutch": {
+		"aan", "al", "als", "bij", "dan", "dat", "de", "die", "dit", "door", "een", "en", "er",
+		"had", "heb", "het", "hij", "hoe", "ik", "in", "is", "je", "kan", "maar", "me", "met",
+		"mij", "na", "naar", "niet", "nog", "of", "om", "ook", "op", "over", "te", "tot", "uit",
+		"van", "voor", "was", "wat", "we", "wel", "wie", "zal", "ze", "zich", "zijn", "zo",
+	},
+}
+
+// languageCodes maps iso 639-1 codes to language names
+var languageCodes = map[string]string{
+	"en": "english", "fr": "french", "de": "german", "es": "spanish",
+	"it": "italian", "pt": "portuguese", "nl": "dutch",
+}
+
+// Stopwords returns a filter that removes the given words
+func Stopwords(words ...string) Func {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+
+	return func(tokens []string) ([]string, error) {
+		out := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			if _, ok := set[token]; !ok {
+				out = append(out, token)
+			}
+		}
+		return out, nil
+	}
+}
+
+// StopwordsLanguage returns a filter that removes the built in
+// stopwords for the language name or iso 639-1 code, like "french" or "fr"
+func StopwordsLanguage(language string) (Func, error) {
+	language = strings.ToLower(language)
+	if name, ok := languageCodes[language]; ok {
+		language = name
+	}
+
+	if language == "english" {
+		words := make([]string, 0, len(stopwords))
+		for word := range stopwords {
+			words = append(words, word)
+		}
+		return Stopwords(words...), nil
+	}
+
+	words, ok := languageStopwords[language]
+	if !ok {
+		return nil, fmt.Errorf("stopwords for language '%s' not found", language)
+	}
+
+	return Stopwords(words...), nil
+}
+
+// LoadStopwords returns a filter that removes the stopwords listed in
+// the file. The file has one word per line, blank lines are ignored and
+// anything after a # or | is a comment
+func LoadStopwords(path string) (Func, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.IndexAny(line, "#|"); index >= 0 {
+			line = line[:index]
+		}
+
+		line = strings.TrimSpace(line)
+		if line != "" {
+			words = append(words, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stopwords file: %v", err)
+	}
+
+	return Stopwords(words...), nil
+}
@@ -0,0 +1,69 @@
+package filters
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStopwordsLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		tokens   []string
+		result   []string
+	}{
+		{"english", []string{"the", "quick", "fox"}, []string{"quick", "fox"}},
+		{"en", []string{"the", "quick", "fox"}, []string{"quick", "fox"}},
+		{"french", []string{"le", "chat", "et", "la", "souris"}, []string{"chat", "souris"}},
+		{"de", []string{"der", "hund", "und", "die", "katze"}, []string{"hund", "katze"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.language, func(t *testing.T) {
+			filter, err := StopwordsLanguage(test.language)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			result, err := filter(test.tokens)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("Expected %v, got %v", test.result, result)
+			}
+		})
+	}
+
+	if _, err := StopwordsLanguage("klingon"); err == nil {
+		t.Errorf("Expected error for unknown language")
+	}
+}
+
+func TestLoadStopwords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stopwords.txt")
+	content := "# custom stopwords\nfoo\n\nbar | trailing comment\n  baz  \n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write stopwords file: %v", err)
+	}
+
+	filter, err := LoadStopwords(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result, err := filter([]string{"foo", "hello", "bar", "baz", "world"})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	want := []string{"hello", "world"}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Expected %v, got %v", want, result)
+	}
+
+	if _, err := LoadStopwords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
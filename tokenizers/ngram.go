@@ -2,23 +2,54 @@ package tokenizers
 
 import (
 	"fmt"
+	"math"
 	"strings"
-
-	"golang.org/x/text/transform"
+	"unicode"
 )
 
 func init() {
 	SetTokenizer("ngram", NewNGram(3, 10))
+	SetTokenizer("edge_ngram", NewEdgeNGram(1, 10))
 }
 
 type NGram struct {
 	min   int
 	max   int
+	edge  bool            // Only generate n-grams anchored at the start of a token
+	alnum bool            // Only generate n-grams from runs of letters and digits
 	index map[string]bool // Index of n-grams for efficient searching
 }
 
+// NGramOptions are the options used to create a new NGram tokenizer
+type NGramOptions struct {
+	Min int // Minimum n-gram length in runes
+	Max int // Maximum n-gram length in runes
+
+	// Edge will only generate n-grams from the start of the value
+	// (or the start of each word if LettersDigits is set) which is
+	// useful for search as you type
+	Edge bool
+
+	// LettersDigits will split the value on anything that is not a
+	// letter or a digit so n-grams never contain spaces or punctuation
+	LettersDigits bool
+}
+
 // NewNGram returns a new NGram tokenizer
 func NewNGram(min, max int) *NGram {
+	return NewNGramOptions(NGramOptions{Min: min, Max: max})
+}
+
+// NewEdgeNGram returns a new NGram tokenizer that only
+// generates prefixes of each word, for search as you type
+func NewEdgeNGram(min, max int) *NGram {
+	return NewNGramOptions(NGramOptions{Min: min, Max: max, Edge: true, LettersDigits: true})
+}
+
+// NewNGramOptions returns a new NGram tokenizer with the given options
+func NewNGramOptions(options NGramOptions) *NGram {
+	min, max := options.Min, options.Max
+
 	// If min is greater than max, swap them
 	if min > max {
 		min, max = max, min
@@ -27,22 +58,34 @@ func NewNGram(min, max int) *NGram {
 	return &NGram{
 		min:   min,
 		max:   max,
+		edge:  options.Edge,
+		alnum: options.LettersDigits,
 		index: make(map[string]bool), // Initialize the index map
 	}
 }
 
+// Options returns the options the NGram tokenizer was created with
+func (n *NGram) Options() NGramOptions {
+	return NGramOptions{Min: n.min, Max: n.max, Edge: n.edge, LettersDigits: n.alnum}
+}
+
+// Copy returns a new NGram tokenizer with the same options
+func (n *NGram) Copy() Tokenizer {
+	return NewNGramOptions(n.Options())
+}
+
 // Process takes a string value, generates n-grams, and fills out the index
 func (n *NGram) Process(val string) error {
-	// Clean the input string
-	val = cleanNGramStr(val)
+	var nGrams []string
+	for _, part := range n.parts(val) {
+		nGrams = append(nGrams, generateNGrams(part, n.min, n.max, n.edge)...)
+	}
 
-	// After clean check if the string is empty
-	if len(val) < n.min {
+	// After clean check if there was anything long enough to index
+	if len(nGrams) == 0 {
 		return fmt.Errorf("input shorter than min n-gram length")
 	}
 
-	nGrams := generateNGrams(val, n.min, n.max)
-
 	// Index the n-grams
 	for _, nGram := range nGrams {
 		n.index[nGram] = true
@@ -53,40 +96,84 @@ func (n *NGram) Process(val string) error {
 
 // Tokenize generates all possible n-grams from the input string
 func (n *NGram) ToSearch(val string) ([]string, error) {
-	// Clean the input string
-	val = cleanNGramStr(val)
+	var search []string
+	for _, part := range n.parts(val) {
+		if len(part) < n.min {
+			continue
+		}
 
-	// After clean check if the string is empty
-	if len(val) < n.min {
-		return nil, fmt.Errorf("input shorter than min n-gram length")
+		// Check if val is larger than the max n-gram length
+		if len(part) > n.max {
+			part = part[:n.max]
+		}
+
+		search = append(search, string(part))
 	}
 
-	// Check if val is larger than the max n-gram length
-	if len(val) > n.max {
-		val = val[:n.max]
+	// After clean check if the string is empty
+	if len(search) == 0 {
+		return nil, fmt.Errorf("input shorter than min n-gram length")
 	}
 
-	return []string{val}, nil
+	return search, nil
 }
 
 // Search checks if all specified n-grams exist in the index
 func (n *NGram) Search(vals []string) (bool, error) {
-	// Expected value is a single value of the string we are searching for
-	if len(vals) != 1 {
-		return false, fmt.Errorf("expected a single value, got %d", len(vals))
+	if len(vals) == 0 {
+		return false, fmt.Errorf("expected at least one value")
 	}
 
-	// Check if the value is in the index
-	if _, exists := n.index[vals[0]]; !exists {
-		return false, nil
+	// Check if the values are in the index
+	for _, val := range vals {
+		if _, exists := n.index[val]; !exists {
+			return false, nil
+		}
 	}
 
 	return true, nil
 }
 
+// Score is the share of the n-grams found in the index, divided
+// by the square root of the index size so short values count for more
+func (n *NGram) Score(vals []string) float64 {
+	if len(vals) == 0 || len(n.index) == 0 {
+		return 0
+	}
+
+	found := 0
+	for _, val := range vals {
+		if n.index[val] {
+			found++
+		}
+	}
+
+	return float64(found) / float64(len(vals)) / math.Sqrt(float64(len(n.index)))
+}
+
+// parts cleans the value and splits it into the rune
+// slices that n-grams should be generated from
+func (n *NGram) parts(val string) [][]rune {
+	// Clean the input string
+	val = cleanNGramStr(val)
+
+	if !n.alnum {
+		return [][]rune{[]rune(val)}
+	}
+
+	var parts [][]rune
+	for _, word := range strings.FieldsFunc(val, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		parts = append(parts, []rune(word))
+	}
+
+	return parts
+}
+
 func cleanNGramStr(val string) string {
 	// Remove accents from the string
-	val, _, _ = transform.String(normalizer, val)
+	val, _ = removeAccents(val)
 
 	// Lowercase the string
 	val = strings.ToLower(val)
@@ -94,11 +181,18 @@ func cleanNGramStr(val string) string {
 	return val
 }
 
-func generateNGrams(val string, min, max int) []string {
+// generateNGrams works on runes so min and max are
+// counted in characters and grams are always valid UTF-8
+func generateNGrams(val []rune, min, max int, edge bool) []string {
 	var nGrams []string
 	for i := 0; i <= len(val)-min; i++ {
 		for j := min; j <= max && i+j <= len(val); j++ {
-			nGrams = append(nGrams, val[i:i+j])
+			nGrams = append(nGrams, string(val[i:i+j]))
+		}
+
+		// Edge n-grams are only anchored at the start
+		if edge {
+			break
 		}
 	}
 	return nGrams
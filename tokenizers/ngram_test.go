@@ -230,6 +230,115 @@ func TestNGramSearch(t *testing.T) {
 	}
 }
 
+func TestNGramUnicode(t *testing.T) {
+	n := NewNGram(2, 2)
+
+	// Multi-byte runes should be counted as single characters
+	err := n.Process("日本語")
+	if err != nil {
+		t.Fatalf("NGram.Process() failed: %v", err)
+	}
+
+	want := map[string]bool{"日本": true, "本語": true}
+	if len(n.index) != len(want) {
+		t.Errorf("NGram.Process() failed count: got %v, want %v", n.index, want)
+	}
+	for k := range want {
+		if !n.index[k] {
+			t.Errorf("NGram.Process() missing %q: got %v", k, n.index)
+		}
+	}
+
+	// Search values should be truncated by runes, not bytes
+	n = NewNGram(1, 2)
+	val, err := n.ToSearch("日本語")
+	if err != nil {
+		t.Fatalf("NGram.ToSearch() failed: %v", err)
+	}
+	if len(val) != 1 || val[0] != "日本" {
+		t.Errorf("NGram.ToSearch() failed: got %v, want %v", val, []string{"日本"})
+	}
+
+	// A two rune string is shorter than a min of three even though it is six bytes
+	n = NewNGram(3, 3)
+	if err := n.Process("日本"); err == nil {
+		t.Errorf("NGram.Process() expected error for input shorter than min")
+	}
+}
+
+func TestNGramEdge(t *testing.T) {
+	n := NewEdgeNGram(1, 4)
+
+	err := n.Process("Hello World")
+	if err != nil {
+		t.Fatalf("NGram.Process() failed: %v", err)
+	}
+
+	want := map[string]bool{
+		"h": true, "he": true, "hel": true, "hell": true,
+		"w": true, "wo": true, "wor": true, "worl": true,
+	}
+	if len(n.index) != len(want) {
+		t.Errorf("NGram.Process() failed count: got %v, want %v", n.index, want)
+	}
+	for k := range want {
+		if !n.index[k] {
+			t.Errorf("NGram.Process() missing %q: got %v", k, n.index)
+		}
+	}
+
+	testCases := []struct {
+		search string
+		match  bool
+	}{
+		{"he", true},
+		{"wor", true},
+		{"hel wo", true},
+		{"ell", false},
+		{"orld", false},
+	}
+
+	for _, tc := range testCases {
+		val, err := n.ToSearch(tc.search)
+		if err != nil {
+			t.Errorf("NGram.ToSearch() failed: %v", err)
+		}
+
+		match, err := n.Search(val)
+		if err != nil {
+			t.Errorf("NGram.Search() failed: %v", err)
+		}
+
+		if match != tc.match {
+			t.Errorf("NGram.Search(%q) failed: got %v, want %v", tc.search, match, tc.match)
+		}
+	}
+}
+
+func TestNGramLettersDigits(t *testing.T) {
+	n := NewNGramOptions(NGramOptions{Min: 2, Max: 3, LettersDigits: true})
+
+	err := n.Process("wi-fi 5g")
+	if err != nil {
+		t.Fatalf("NGram.Process() failed: %v", err)
+	}
+
+	want := map[string]bool{"wi": true, "fi": true, "5g": true}
+	if len(n.index) != len(want) {
+		t.Errorf("NGram.Process() failed count: got %v, want %v", n.index, want)
+	}
+	for k := range want {
+		if !n.index[k] {
+			t.Errorf("NGram.Process() missing %q: got %v", k, n.index)
+		}
+	}
+
+	// Punctuation only input has nothing to index
+	if err := NewNGramOptions(NGramOptions{Min: 1, Max: 3, LettersDigits: true}).Process("--"); err == nil {
+		t.Errorf("NGram.Process() expected error for input without letters or digits")
+	}
+}
+
 // BenchmarkNGramProcess benchmarks the Process method of the NGram struct
 func BenchmarkNGramProcessSmall(b *testing.B) {
 	n := NewNGram(1, 10)
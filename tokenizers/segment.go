@@ -0,0 +1,246 @@
+package tokenizers
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordClass is a simplified version of the UAX#29 word break property
+type wordClass int
+
+const (
+	wbOther        wordClass = iota
+	wbLetter                 // ALetter and Hebrew_Letter
+	wbNumeric                // Numeric
+	wbKatakana               // Katakana
+	wbIdeographic            // Han and Hiragana, every rune is its own word
+	wbComplex                // South East Asian scripts written without spaces
+	wbExtendNumLet           // Connector punctuation like "_"
+	wbMidLetter              // Joins letters, like ":" in "Ärz:te"
+	wbMidNum                 // Joins numbers, like "," in "1,000"
+	wbMidNumLet              // Joins letters or numbers, like "." or "'"
+	wbExtend                 // Combining marks that attach to the previous rune
+)
+
+// segment is a single word found in a string with
+// the byte offsets it was found at in the original string
+type segment struct {
+	text  string
+	class wordClass
+	start int
+	end   int
+}
+
+// complexScripts are scripts that do not use spaces between words
+// and would need a dictionary to segment properly
+var complexScripts = []*unicode.RangeTable{unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar}
+
+// cjkScripts are scripts that are bigrammed by the CJK tokenizer
+var cjkScripts = []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul}
+
+func classifyRune(r rune) wordClass {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me):
+		return wbExtend
+	case unicode.Is(unicode.Katakana, r) || r == 'ー':
+		return wbKatakana
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		return wbIdeographic
+	case unicode.IsLetter(r) && unicode.In(r, complexScripts...):
+		return wbComplex
+	case unicode.IsLetter(r):
+		return wbLetter
+	case unicode.IsNumber(r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	}
+
+	switch r {
+	case ':', '·', '·', '״', '‧', '︓', '﹕', '：':
+		return wbMidLetter
+	case ',', ';', ';', '։', '،', '؍', '٬', '߸', '⁄', '︐', '︔', '﹐', '﹔', '，', '；':
+		return wbMidNum
+	case '.', '\'', '‘', '’', '․', '﹒', '＇', '．':
+		return wbMidNumLet
+	}
+
+	return wbOther
+}
+
+// joins reports whether a rune of class next continues
+// a word whose last rune was of class prev
+func joins(prev, next wordClass) bool {
+	switch prev {
+	case wbLetter, wbNumeric:
+		return next == wbLetter || next == wbNumeric || next == wbExtendNumLet
+	case wbKatakana:
+		return next == wbKatakana || next == wbExtendNumLet
+	case wbExtendNumLet:
+		return next == wbLetter || next == wbNumeric || next == wbKatakana || next == wbExtendNumLet
+	case wbComplex:
+		return next == wbComplex
+	}
+	return false
+}
+
+// joinsAcross reports whether a mid rune of class mid
+// joins a word ending in prev to a word starting with next
+func joinsAcross(prev, mid, next wordClass) bool {
+	switch {
+	case prev == wbLetter && next == wbLetter:
+		return mid == wbMidLetter || mid == wbMidNumLet
+	case prev == wbNumeric && next == wbNumeric:
+		return mid == wbMidNum || mid == wbMidNumLet
+	}
+	return false
+}
+
+// segmentWords splits a string into words following the
+// UAX#29 word boundary rules, dropping anything that
+// does not contain a letter, number or ideograph
+func segmentWords(str string) []segment {
+	type position struct {
+		r     rune
+		class wordClass
+		start int
+	}
+
+	var runes []position
+	for i, r := range str {
+		runes = append(runes, position{r: r, class: classifyRune(r), start: i})
+	}
+
+	// nextClass returns the class of the next rune after i skipping combining marks
+	nextClass := func(i int) wordClass {
+		for j := i + 1; j < len(runes); j++ {
+			if runes[j].class != wbExtend {
+				return runes[j].class
+			}
+		}
+		return wbOther
+	}
+
+	var segments []segment
+	start, end := -1, -1
+	class, last := wbOther, wbOther
+
+	flush := func() {
+		if start >= 0 && class != wbExtendNumLet {
+			segments = append(segments, segment{text: str[start:end], class: class, start: start, end: end})
+		}
+		start, end = -1, -1
+		class, last = wbOther, wbOther
+	}
+
+	for i, p := range runes {
+		size := utf8.RuneLen(p.r)
+
+		// Combining marks always stay with the rune before them
+		if p.class == wbExtend {
+			if start >= 0 {
+				end = p.start + size
+			}
+			continue
+		}
+
+		if start >= 0 {
+			if joins(last, p.class) || joinsAcross(last, p.class, nextClass(i)) {
+				// Words starting with a connector take the class of what follows it
+				if class == wbExtendNumLet && p.class != wbExtendNumLet {
+					class = p.class
+				}
+				// Mid runes take on the class of the word they are inside
+				if p.class != wbMidLetter && p.class != wbMidNum && p.class != wbMidNumLet {
+					last = p.class
+				}
+				end = p.start + size
+				continue
+			}
+			flush()
+		}
+
+		switch p.class {
+		case wbLetter, wbNumeric, wbKatakana, wbIdeographic, wbComplex, wbExtendNumLet:
+			start, end = p.start, p.start+size
+			class, last = p.class, p.class
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// clusters splits a string into runes with
+// their combining marks still attached
+func clusters(str string) []string {
+	var out []string
+	for _, r := range str {
+		if len(out) > 0 && unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me) {
+			out[len(out)-1] += string(r)
+			continue
+		}
+		out = append(out, string(r))
+	}
+	return out
+}
+
+// bigrams returns overlapping pairs of the given
+// units or the single unit if there is only one
+func bigrams(units []string) []string {
+	if len(units) == 1 {
+		return units
+	}
+
+	out := make([]string, 0, len(units)-1)
+	for i := 0; i < len(units)-1; i++ {
+		out = append(out, units[i]+units[i+1])
+	}
+	return out
+}
+
+// normalizeToken lowercases and removes apostrophes. Accents
+// are only removed from scripts where marks are decoration
+// and not part of the spelling, like latin, greek and cyrillic
+func normalizeToken(token string) string {
+	token = strings.ToLower(token)
+	token = strings.NewReplacer("'", "", "’", "").Replace(token)
+
+	for _, r := range token {
+		if unicode.IsLetter(r) && !unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic) {
+			return token
+		}
+	}
+
+	folded, err := removeAccents(token)
+	if err != nil {
+		return token
+	}
+	return folded
+}
+
+// searchInOrder checks if the search words appear in order within words
+func searchInOrder(words []string, val []string) bool {
+	// Start index for search
+	searchIndex := 0
+
+	for _, searchWord := range val {
+		found := false
+		for i := searchIndex; i < len(words); i++ {
+			if words[i] == searchWord {
+				// If the word is found, update searchIndex to start from the next word
+				searchIndex = i + 1
+				found = true
+				break // Break the inner loop and continue with the next searchWord
+			}
+		}
+		// If any of the words is not found, return false
+		if !found {
+			return false
+		}
+	}
+
+	// If all words were found in order, return true
+	return true
+}
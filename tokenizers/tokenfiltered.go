@@ -0,0 +1,83 @@
+package tokenizers
+
+import (
+	"github.com/brianvoe/gofindit/tokenizers/filters"
+)
+
+func init() {
+	SetTokenizer("soundex", NewTokenFiltered(NewWords(), filters.Soundex))
+	SetTokenizer("refined_soundex", NewTokenFiltered(NewWords(), filters.RefinedSoundex))
+	SetTokenizer("double_metaphone", NewTokenFiltered(NewWords(), filters.DoubleMetaphone))
+}
+
+// TokenFiltered runs token filters on the tokens of the wrapped
+// tokenizer, for both the value and the search, so they are filtered
+// alike. Search matches the filtered tokens in order the same as Words
+type TokenFiltered struct {
+	tokenizer Tokenizer
+	filters   []filters.Func
+	tokens    []string
+}
+
+// NewTokenFiltered returns a tokenizer that runs the token
+// filters, in order, on the tokens of the given tokenizer
+func NewTokenFiltered(tokenizer Tokenizer, filters ...filters.Func) *TokenFiltered {
+	return &TokenFiltered{
+		tokenizer: tokenizer,
+		filters:   filters,
+	}
+}
+
+// Copy returns a new TokenFiltered tokenizer with the same filters
+// and a copy of the wrapped tokenizer when it can be copied
+func (t *TokenFiltered) Copy() Tokenizer {
+	tokenizer := t.tokenizer
+	if copier, ok := tokenizer.(Copier); ok {
+		tokenizer = copier.Copy()
+	}
+
+	return NewTokenFiltered(tokenizer, t.filters...)
+}
+
+// Process will tokenize the value and keep the filtered tokens
+func (t *TokenFiltered) Process(val string) error {
+	tokens, err := t.ToSearch(val)
+	if err != nil {
+		return err
+	}
+	t.tokens = tokens
+
+	return nil
+}
+
+// ToSearch will tokenize the search value and run the filters on it
+func (t *TokenFiltered) ToSearch(val string) ([]string, error) {
+	tokens, err := t.tokenizer.ToSearch(val)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filter := range t.filters {
+		tokens, err = filter(tokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+// Search checks if the search tokens appear in order in the filtered tokens
+func (t *TokenFiltered) Search(val []string) (bool, error) {
+	return searchInOrder(t.tokens, val), nil
+}
+
+// Score scores the filtered tokens for the search tokens
+func (t *TokenFiltered) Score(val []string) float64 {
+	return scoreTokens(t.tokens, val)
+}
+
+// Tokens returns the filtered tokens of the last processed value
+func (t *TokenFiltered) Tokens() []string {
+	return t.tokens
+}
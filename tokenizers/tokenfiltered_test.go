@@ -0,0 +1,51 @@
+package tokenizers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brianvoe/gofindit/tokenizers/filters"
+)
+
+func TestTokenFiltered(t *testing.T) {
+	length := filters.Length(3, 0)
+	f := NewTokenFiltered(NewWords(), filters.RemoveStopwords, length)
+
+	if err := f.Process("The cat is on a mat"); err != nil {
+		t.Fatalf("TokenFiltered.Process() failed: %v", err)
+	}
+
+	want := []string{"cat", "mat"}
+	if !reflect.DeepEqual(f.Tokens(), want) {
+		t.Errorf("TokenFiltered.Process() = %v, want %v", f.Tokens(), want)
+	}
+
+	// The search value runs through the same filters
+	val, err := f.ToSearch("the mat")
+	if err != nil {
+		t.Fatalf("TokenFiltered.ToSearch() failed: %v", err)
+	}
+	if match, _ := f.Search(val); !match {
+		t.Errorf("TokenFiltered.Search(%v) did not match", val)
+	}
+}
+
+func TestTokenFilteredSoundex(t *testing.T) {
+	tokenizer, err := NewTokenizer("soundex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokenizer.Process("Robert Smith"); err != nil {
+		t.Fatal(err)
+	}
+
+	for search, want := range map[string]bool{"Rupert": true, "rupert smyth": true, "smith robert": false, "Bob": false} {
+		val, err := tokenizer.ToSearch(search)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match, _ := tokenizer.Search(val); match != want {
+			t.Errorf("Search(%q) = %v, want %v", search, match, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tokenizers
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"unicode"
 
@@ -10,7 +11,22 @@ import (
 	"golang.org/x/text/unicode/norm"
 )
 
-var normalizer = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+// normalizers hold chains that remove accents. A chain keeps state while
+// it runs so each call takes its own to be safe across goroutines
+var normalizers = sync.Pool{
+	New: func() any {
+		return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	},
+}
+
+// removeAccents returns the string with its accents removed
+func removeAccents(str string) (string, error) {
+	normalizer := normalizers.Get().(transform.Transformer)
+	defer normalizers.Put(normalizer)
+
+	str, _, err := transform.String(normalizer, str)
+	return str, err
+}
 
 type Tokenizer interface {
 	// Process will take in a string value and
@@ -25,6 +41,39 @@ type Tokenizer interface {
 	Search(val []string) (bool, error)
 }
 
+// Scorer is implemented by tokenizers that can score how
+// relevant the processed value is to the search tokens
+type Scorer interface {
+	Score(val []string) float64
+}
+
+// scoreTokens scores the words for the search tokens. Each token adds
+// the square root of how often it appears so repeats count for less,
+// and the total is divided by the square root of the number of words
+// so a token in a short value counts for more than in a long one
+func scoreTokens(words []string, val []string) float64 {
+	if len(words) == 0 || len(val) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(val))
+	for _, token := range val {
+		counts[token] = 0
+	}
+	for _, word := range words {
+		if _, ok := counts[word]; ok {
+			counts[word]++
+		}
+	}
+
+	score := 0.0
+	for _, token := range val {
+		score += math.Sqrt(float64(counts[token]))
+	}
+
+	return score / float64(len(val)) / math.Sqrt(float64(len(words)))
+}
+
 type storage struct {
 	tokenizers map[string]Tokenizer
 
@@ -47,6 +96,28 @@ func GetTokenizer(name string, config map[string]any) (Tokenizer, error) {
 	return tokenizer, nil
 }
 
+// Copier is implemented by tokenizers that can return a new
+// tokenizer with the same options and none of the processed values
+type Copier interface {
+	Copy() Tokenizer
+}
+
+// NewTokenizer returns a copy of a tokenizer from the store so
+// each value can be processed without sharing state
+func NewTokenizer(name string) (Tokenizer, error) {
+	tokenizer, err := GetTokenizer(name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	copier, ok := tokenizer.(Copier)
+	if !ok {
+		return nil, fmt.Errorf("tokenizer type '%s' cannot be copied", name)
+	}
+
+	return copier.Copy(), nil
+}
+
 // SetTokenizer sets a tokenizer in the store
 // will overwrite if it already exists
 func SetTokenizer(name string, tokenizer Tokenizer) {
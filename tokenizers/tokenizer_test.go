@@ -1,6 +1,9 @@
 package tokenizers
 
-import "testing"
+import (
+	"sync"
+	"testing"
+)
 
 // build simple tokenizer for tests
 type SimpleTokenizer struct {
@@ -60,3 +63,45 @@ func TestTokenizerSetGetDelete(t *testing.T) {
 		t.Errorf("expected error getting tokenizer")
 	}
 }
+
+func TestNewTokenizer(t *testing.T) {
+	tokenizer, err := NewTokenizer("words")
+	if err != nil {
+		t.Fatalf("error getting tokenizer: %v", err)
+	}
+
+	// Copies do not share state with the registered tokenizer
+	registered, _ := GetTokenizer("words", nil)
+	if tokenizer == registered {
+		t.Errorf("expected a copy of the tokenizer")
+	}
+
+	// Tokenizers without a Copy method cannot be copied
+	SetTokenizer("simple", &SimpleTokenizer{})
+	defer DeleteTokenizer("simple")
+	if _, err := NewTokenizer("simple"); err == nil {
+		t.Errorf("expected error copying tokenizer without Copy")
+	}
+
+	if _, err := NewTokenizer("nope"); err == nil {
+		t.Errorf("expected error for unknown tokenizer")
+	}
+}
+
+func TestRemoveAccents_concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				str, err := removeAccents("Crème Brûlée")
+				if err != nil || str != "Creme Brulee" {
+					t.Errorf("expected Creme Brulee, got %q %v", str, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
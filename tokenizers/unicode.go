@@ -0,0 +1,69 @@
+package tokenizers
+
+import (
+	"errors"
+)
+
+func init() {
+	SetTokenizer("unicode", NewUnicode())
+}
+
+// Unicode splits text on UAX#29 word boundaries so words
+// with inner punctuation like "can't" or "3.14" stay together.
+// Ideographs are indexed one per character and scripts without
+// spaces like thai and khmer fall back to character bigrams
+type Unicode struct {
+	words []string
+}
+
+// NewUnicode returns a new UAX#29 word boundary tokenizer
+func NewUnicode() *Unicode {
+	return &Unicode{}
+}
+
+// Copy returns a new Unicode tokenizer
+func (u *Unicode) Copy() Tokenizer {
+	return NewUnicode()
+}
+
+// Process will take in a string value and
+// use it to fill out the struct fields
+func (u *Unicode) Process(str string) error {
+	var err error
+	u.words, err = u.ToSearch(str)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ToSearch will return the words to search
+func (u *Unicode) ToSearch(str string) ([]string, error) {
+	if str == "" {
+		return nil, errors.New("empty string")
+	}
+
+	var tokens []string
+	for _, seg := range segmentWords(str) {
+		// Dictionary free fallback for scripts without spaces
+		if seg.class == wbComplex {
+			tokens = append(tokens, bigrams(clusters(seg.text))...)
+			continue
+		}
+
+		tokens = append(tokens, normalizeToken(seg.text))
+	}
+
+	return tokens, nil
+}
+
+// Search checks if the provided words appear in order within the words
+func (u *Unicode) Search(val []string) (bool, error) {
+	return searchInOrder(u.words, val), nil
+}
+
+// Score scores the words for the search tokens
+func (u *Unicode) Score(val []string) float64 {
+	return scoreTokens(u.words, val)
+}
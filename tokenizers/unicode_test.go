@@ -0,0 +1,109 @@
+package tokenizers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeToSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			text:    "",
+			wantErr: true,
+		},
+		{
+			name: "simple",
+			text: "Hello, World!",
+			want: []string{"hello", "world"},
+		},
+		{
+			name: "apostrophe",
+			text: "It's what's left",
+			want: []string{"its", "whats", "left"},
+		},
+		{
+			name: "numbers",
+			text: "Pi is 3.14 and 1,000 is more",
+			want: []string{"pi", "is", "3.14", "and", "1,000", "is", "more"},
+		},
+		{
+			name: "underscore",
+			text: "snake_case name",
+			want: []string{"snake_case", "name"},
+		},
+		{
+			name: "accented",
+			text: "Héllö Wörld",
+			want: []string{"hello", "world"},
+		},
+		{
+			name: "ideographs",
+			text: "東京都",
+			want: []string{"東", "京", "都"},
+		},
+		{
+			name: "katakana",
+			text: "カメラ 2台",
+			want: []string{"カメラ", "2", "台"},
+		},
+		{
+			name: "thai",
+			text: "สวัสดี",
+			want: []string{"สวั", "วัส", "สดี"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUnicode()
+			got, err := u.ToSearch(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unicode.ToSearch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unicode.ToSearch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnicodeSearch(t *testing.T) {
+	u := NewUnicode()
+	if err := u.Process("Canon PowerShot カメラ 東京"); err != nil {
+		t.Fatalf("Unicode.Process() failed: %v", err)
+	}
+
+	tests := []struct {
+		search string
+		match  bool
+	}{
+		{"canon", true},
+		{"カメラ", true},
+		{"東京", true},
+		{"nikon", false},
+	}
+
+	for _, tt := range tests {
+		val, err := u.ToSearch(tt.search)
+		if err != nil {
+			t.Errorf("Unicode.ToSearch() failed: %v", err)
+		}
+
+		match, err := u.Search(val)
+		if err != nil {
+			t.Errorf("Unicode.Search() failed: %v", err)
+		}
+
+		if match != tt.match {
+			t.Errorf("Unicode.Search(%q) = %v, want %v", tt.search, match, tt.match)
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"errors"
 	"strings"
 	"unicode"
-
-	"golang.org/x/text/transform"
 )
 
 func init() {
@@ -22,6 +20,11 @@ func NewWords() *Words {
 	return &Words{}
 }
 
+// Copy returns a new Words tokenizer
+func (w *Words) Copy() Tokenizer {
+	return NewWords()
+}
+
 // Process will take in an any value and
 // use it to fill out the struct fields
 func (w *Words) Process(str string) error {
@@ -44,7 +47,7 @@ func (w *Words) ToSearch(str string) ([]string, error) {
 	var err error
 
 	// Remove accents from the string
-	str, _, err = transform.String(normalizer, str)
+	str, err = removeAccents(str)
 	if err != nil {
 		return nil, err
 	}
@@ -66,25 +69,10 @@ func (w *Words) ToSearch(str string) ([]string, error) {
 
 // Search checks if the provided words appear in order within the Words struct's words slice.
 func (w *Words) Search(val []string) (bool, error) {
-	// Start index for search
-	searchIndex := 0
-
-	for _, searchWord := range val {
-		found := false
-		for i := searchIndex; i < len(w.words); i++ {
-			if w.words[i] == searchWord {
-				// If the word is found, update searchIndex to start from the next word
-				searchIndex = i + 1
-				found = true
-				break // Break the inner loop and continue with the next searchWord
-			}
-		}
-		// If any of the words is not found, return false
-		if !found {
-			return false, nil
-		}
-	}
+	return searchInOrder(w.words, val), nil
+}
 
-	// If all words were found in order, return true
-	return true, nil
+// Score scores the words for the search tokens
+func (w *Words) Score(val []string) float64 {
+	return scoreTokens(w.words, val)
 }
@@ -1,6 +1,7 @@
 package tokenizers
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
@@ -170,3 +171,30 @@ func TestWordsSearch(t *testing.T) {
 		})
 	}
 }
+
+func TestWordsScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		search []string
+		want   float64
+	}{
+		{name: "exact", text: "Red", search: []string{"red"}, want: 1},
+		{name: "missing", text: "Blue shoes", search: []string{"red"}, want: 0},
+		{name: "longer", text: "Red shoes for the rain", search: []string{"red"}, want: 1 / math.Sqrt(5)},
+		{name: "repeated", text: "red red shoes", search: []string{"red"}, want: math.Sqrt(2) / math.Sqrt(3)},
+		{name: "half", text: "red shoes", search: []string{"red", "boots"}, want: 0.5 / math.Sqrt(2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words := NewWords()
+			if err := words.Process(tt.text); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := words.Score(tt.search); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Words.Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
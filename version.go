@@ -0,0 +1,169 @@
+package gofindit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrVersionConflict is returned when a document does not meet the
+// conditions of a write, check for it with errors.Is
+var ErrVersionConflict = errors.New("version conflict")
+
+// VersionConflictError has the current version and seq no of the
+// document that did not meet the conditions. They are 0 if it is
+// missing, other than the version of one deleted with an external version
+type VersionConflictError struct {
+	ID      string
+	Version int64
+	SeqNo   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict for %s, current version %d and seq no %d", e.ID, e.Version, e.SeqNo)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// WriteOptions are the conditions of an update or delete. Every
+// write gives a document the next seq no of the index and a version
+// one higher than the document it replaced, starting at 1
+type WriteOptions struct {
+	IfVersion int64 `json:"if_version"` // Only write if the document is at this version
+	IfSeqNo   int64 `json:"if_seq_no"`  // Only write if the document was last written at this seq no
+
+	// Version is an external version, like from another database, used
+	// instead of the next version. It must be higher than the current one
+	Version int64 `json:"version"`
+}
+
+func (wo *WriteOptions) Validate() error {
+	if wo.IfVersion < 0 || wo.IfSeqNo < 0 || wo.Version < 0 {
+		return fmt.Errorf("versions and seq nos cannot be negative")
+	}
+
+	if wo.Version > 0 && (wo.IfVersion > 0 || wo.IfSeqNo > 0) {
+		return fmt.Errorf("version cannot be used with if_version or if_seq_no")
+	}
+
+	return nil
+}
+
+// check returns a VersionConflictError if the current document, nil
+// if there is none, does not meet the conditions. Version is the version
+// of the current document or the one it was deleted at, 0 if neither
+func (wo *WriteOptions) check(id string, current *Document, version int64) error {
+	if wo.IfVersion == 0 && wo.IfSeqNo == 0 && wo.Version == 0 {
+		return nil
+	}
+
+	conflict := &VersionConflictError{ID: id, Version: version}
+	if current != nil {
+		conflict.SeqNo = current.seqNo
+	}
+
+	if wo.IfVersion > 0 && (current == nil || wo.IfVersion != conflict.Version) {
+		return conflict
+	}
+	if wo.IfSeqNo > 0 && wo.IfSeqNo != conflict.SeqNo {
+		return conflict
+	}
+	if wo.Version > 0 && wo.Version <= conflict.Version {
+		return conflict
+	}
+
+	return nil
+}
+
+// tombstone is the version a document was deleted at with an external version
+type tombstone struct {
+	version int64
+	expires time.Time
+}
+
+// tombstoneExpiry is when the tombstone added for the id expires
+type tombstoneExpiry struct {
+	id      string
+	expires time.Time
+}
+
+// tombstoneVersion returns the version the document was deleted at,
+// 0 if it was not deleted with an external version or the tombstone
+// expired. The lock must be held
+func (i *Index) tombstoneVersion(id string) int64 {
+	i.expireTombstones()
+	return i.tombstones[id].version
+}
+
+// addTombstone keeps the version the document was deleted at
+// for the tombstone ttl. The lock must be held
+func (i *Index) addTombstone(id string, version int64) {
+	i.expireTombstones()
+
+	expires := time.Now().Add(i.TombstoneTTL)
+	i.tombstones[id] = tombstone{version: version, expires: expires}
+	i.tombstoneOrder = append(i.tombstoneOrder, tombstoneExpiry{id: id, expires: expires})
+}
+
+// expireTombstones removes the tombstones that expired, oldest first.
+// An id deleted again has a newer tombstone that is left alone
+func (i *Index) expireTombstones() {
+	now := time.Now()
+	n := 0
+	for ; n < len(i.tombstoneOrder) && now.After(i.tombstoneOrder[n].expires); n++ {
+		expired := i.tombstoneOrder[n]
+		if i.tombstones[expired.id].expires.Equal(expired.expires) {
+			delete(i.tombstones, expired.id)
+		}
+	}
+	i.tombstoneOrder = i.tombstoneOrder[n:]
+}
+
+// setVersion gives the document the next seq no and the version after
+// the previous version, 0 if there is none, or the external version
+func (i *Index) setVersion(doc *Document, previous int64, external int64) {
+	i.seqNo++
+	doc.seqNo = i.seqNo
+
+	doc.version = previous + 1
+	if external > 0 {
+		doc.version = external
+	}
+}
+
+// Update replaces the document with the id, it must already exist
+func (i *Index) Update(id string, doc any) error {
+	return i.UpdateOptions(id, doc, WriteOptions{})
+}
+
+// UpdateOptions replaces the document with the id if it meets the
+// conditions, otherwise a VersionConflictError is returned
+func (i *Index) UpdateOptions(id string, doc any, options WriteOptions) error {
+	return i.write(BulkOperation{Type: "update", ID: id, Document: doc, WriteOptions: options})
+}
+
+// Delete removes the document with the id
+func (i *Index) Delete(id string) error {
+	return i.DeleteOptions(id, WriteOptions{})
+}
+
+// DeleteOptions removes the document with the id if it meets the
+// conditions, otherwise a VersionConflictError is returned
+func (i *Index) DeleteOptions(id string, options WriteOptions) error {
+	return i.write(BulkOperation{Type: "delete", ID: id, WriteOptions: options})
+}
+
+// write analyzes and applies a single operation
+func (i *Index) write(op BulkOperation) error {
+	doc, err := op.analyze()
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.applyBulk(op, doc)
+}
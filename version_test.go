@@ -0,0 +1,307 @@
+package gofindit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIndex_versions(t *testing.T) {
+	index := New()
+	if err := index.Index("billy", TestRecord{Name: "billy", Score: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Index("sally", TestRecord{Name: "sally", Score: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	hit, err := index.GetHit("billy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit.Version != 1 || hit.SeqNo != 1 {
+		t.Errorf("expected version 1 and seq no 1, got %d and %d", hit.Version, hit.SeqNo)
+	}
+
+	if err := index.Update("billy", TestRecord{Name: "billy", Score: 3}); err != nil {
+		t.Fatal(err)
+	}
+	hit, err = index.GetHit("billy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit.Version != 2 || hit.SeqNo != 3 || hit.Document.(TestRecord).Score != 3 {
+		t.Errorf("expected updated billy at version 2 and seq no 3, got %+v", hit)
+	}
+	if version, seqNo, err := index.GetVersion("billy"); err != nil || version != 2 || seqNo != 3 {
+		t.Errorf("expected GetVersion to return 2 and 3, got %d, %d and %v", version, seqNo, err)
+	}
+	if _, _, err := index.GetVersion("nobody"); err == nil {
+		t.Errorf("expected GetVersion of a missing document to error")
+	}
+
+	// Search hits have the version too
+	res, err := index.SearchHits(SearchQuery{
+		Fields: []SearchQueryField{{Field: "score", Type: "range", Value: [2]int{3, 3}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].Version != 2 || res.Hits[0].SeqNo != 3 {
+		t.Errorf("expected billy hit at version 2 and seq no 3, got %+v", res.Hits)
+	}
+
+	if err := index.Update("nobody", TestRecord{}); err == nil {
+		t.Errorf("expected update of missing document to fail")
+	}
+	if err := index.Delete("sally"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.GetHit("sally"); err == nil {
+		t.Errorf("expected sally to be deleted")
+	}
+	if err := index.Delete("sally"); err == nil {
+		t.Errorf("expected delete of missing document to fail")
+	}
+
+	// Deletes use up a seq no
+	if err := index.Index("sally", TestRecord{Name: "sally"}); err != nil {
+		t.Fatal(err)
+	}
+	if hit, _ := index.GetHit("sally"); hit.Version != 1 || hit.SeqNo != 5 {
+		t.Errorf("expected reindexed sally at version 1 and seq no 5, got %+v", hit)
+	}
+}
+
+func TestIndex_UpdateOptions(t *testing.T) {
+	index := New()
+	if err := index.Index("billy", TestRecord{Name: "billy", Score: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Update("billy", TestRecord{Name: "billy", Score: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// billy is at version 2 and seq no 2
+	tests := []struct {
+		name     string
+		options  WriteOptions
+		conflict bool
+		version  int64
+	}{
+		{"if version", WriteOptions{IfVersion: 2}, false, 3},
+		{"old if version", WriteOptions{IfVersion: 2}, true, 3},
+		{"if seq no", WriteOptions{IfSeqNo: 3}, false, 4},
+		{"old if seq no", WriteOptions{IfSeqNo: 3}, true, 4},
+		{"both", WriteOptions{IfVersion: 4, IfSeqNo: 4}, false, 5},
+		{"both wrong seq no", WriteOptions{IfVersion: 5, IfSeqNo: 4}, true, 5},
+		{"external", WriteOptions{Version: 100}, false, 100},
+		{"same external", WriteOptions{Version: 100}, true, 100},
+		{"lower external", WriteOptions{Version: 50}, true, 100},
+		{"internal after external", WriteOptions{}, false, 101},
+	}
+
+	for _, tt := range tests {
+		err := index.UpdateOptions("billy", TestRecord{Name: "billy"}, tt.options)
+		if tt.conflict {
+			var conflict *VersionConflictError
+			if !errors.Is(err, ErrVersionConflict) || !errors.As(err, &conflict) {
+				t.Errorf("%s: expected version conflict, got %v", tt.name, err)
+			} else if conflict.ID != "billy" || conflict.Version != tt.version {
+				t.Errorf("%s: expected conflict at version %d, got %+v", tt.name, tt.version, conflict)
+			}
+		} else if err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.name, err)
+		}
+
+		if hit, _ := index.GetHit("billy"); hit.Version != tt.version {
+			t.Errorf("%s: expected version %d, got %d", tt.name, tt.version, hit.Version)
+		}
+	}
+
+	if err := index.UpdateOptions("billy", TestRecord{}, WriteOptions{Version: 200, IfVersion: 101}); err == nil {
+		t.Errorf("expected version with if_version to be invalid")
+	}
+}
+
+func TestIndex_DeleteOptions(t *testing.T) {
+	index := New()
+	if err := index.Index("billy", TestRecord{Name: "billy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := index.DeleteOptions("billy", WriteOptions{IfSeqNo: 2})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("expected version conflict, got %v", err)
+	}
+	if _, err := index.GetHit("billy"); err != nil {
+		t.Errorf("expected billy to not be deleted")
+	}
+
+	if err := index.DeleteOptions("billy", WriteOptions{IfSeqNo: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.GetHit("billy"); err == nil {
+		t.Errorf("expected billy to be deleted")
+	}
+}
+
+func TestIndex_Bulk_externalVersions(t *testing.T) {
+	index := New()
+
+	// Syncing from another database, older versions are skipped
+	res, err := index.Bulk([]BulkOperation{
+		{Type: "upsert", ID: "billy", Document: TestRecord{Name: "billy", Score: 5}, WriteOptions: WriteOptions{Version: 5}},
+		{Type: "upsert", ID: "billy", Document: TestRecord{Name: "billy", Score: 3}, WriteOptions: WriteOptions{Version: 3}},
+		{Type: "upsert", ID: "sally", Document: TestRecord{Name: "sally"}, WriteOptions: WriteOptions{IfVersion: 1}},
+		{Type: "index", ID: "molly", Document: TestRecord{Name: "molly"}, WriteOptions: WriteOptions{IfSeqNo: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item := res.Items[0]; item.Error != nil || item.Version != 5 || item.SeqNo != 1 {
+		t.Errorf("expected billy at version 5 and seq no 1, got %+v", item)
+	}
+	if !errors.Is(res.Items[1].Error, ErrVersionConflict) {
+		t.Errorf("expected older version to conflict, got %v", res.Items[1].Error)
+	}
+	if !errors.Is(res.Items[2].Error, ErrVersionConflict) {
+		t.Errorf("expected if_version on missing document to conflict, got %v", res.Items[2].Error)
+	}
+	if res.Items[3].Error == nil {
+		t.Errorf("expected if_seq_no with index to be invalid")
+	}
+
+	if hit, _ := index.GetHit("billy"); hit.Version != 5 || hit.Document.(TestRecord).Score != 5 {
+		t.Errorf("expected billy to stay at version 5, got %+v", hit)
+	}
+}
+
+func TestBatch_versionConflict(t *testing.T) {
+	index := New()
+	if err := index.Index("billy", TestRecord{Name: "billy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := index.NewBatch()
+	if err := batch.Index("sally", TestRecord{Name: "sally"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Add(BulkOperation{Type: "update", ID: "billy", Document: TestRecord{Name: "billy"}, WriteOptions: WriteOptions{IfVersion: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := batch.Commit(); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("expected version conflict, got %v", err)
+	}
+	if _, err := index.GetHit("sally"); err == nil {
+		t.Errorf("expected sally to be rolled back")
+	}
+	if hit, _ := index.GetHit("billy"); hit.Version != 1 || hit.SeqNo != 1 {
+		t.Errorf("expected billy to be unchanged, got %+v", hit)
+	}
+}
+
+func TestIndex_Delete_externalVersionTombstone(t *testing.T) {
+	index := New()
+
+	// A delete synced from another database at version 7
+	res, err := index.Bulk([]BulkOperation{
+		{Type: "upsert", ID: "billy", Document: TestRecord{Name: "billy", Score: 5}, WriteOptions: WriteOptions{Version: 5}},
+		{Type: "delete", ID: "billy", WriteOptions: WriteOptions{Version: 7}},
+		{Type: "upsert", ID: "billy", Document: TestRecord{Name: "billy", Score: 6}, WriteOptions: WriteOptions{Version: 6}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The older upsert arriving late does not bring it back
+	if !errors.Is(res.Items[2].Error, ErrVersionConflict) {
+		t.Errorf("expected older upsert after the delete to conflict, got %v", res.Items[2].Error)
+	}
+	if _, err := index.GetHit("billy"); err == nil {
+		t.Errorf("expected billy to stay deleted")
+	}
+
+	// Tombstones are rolled back with a batch
+	batch := index.NewBatch()
+	batch.Add(BulkOperation{Type: "upsert", ID: "billy", Document: TestRecord{Name: "billy", Score: 8}, WriteOptions: WriteOptions{Version: 8}})
+	batch.Delete("nobody")
+	if err := batch.Commit(); err == nil {
+		t.Fatal("expected commit to fail")
+	}
+	if index.tombstones["billy"].version != 7 {
+		t.Errorf("expected tombstone at version 7 after rollback, got %d", index.tombstones["billy"].version)
+	}
+
+	// A newer version brings it back and internal writes keep counting
+	if err := index.UpdateOptions("billy", TestRecord{Name: "billy"}, WriteOptions{Version: 8}); err == nil {
+		t.Errorf("expected update of a deleted document to error")
+	}
+	if err := index.Index("billy", TestRecord{Name: "billy", Score: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if hit, _ := index.GetHit("billy"); hit.Version != 8 || hit.Document.(TestRecord).Score != 9 {
+		t.Errorf("expected billy at version 8 after the delete at 7, got %+v", hit)
+	}
+	if _, ok := index.tombstones["billy"]; ok {
+		t.Errorf("expected tombstone to be removed once the document is back")
+	}
+}
+
+func TestIndex_Delete_tombstoneTTL(t *testing.T) {
+	index := NewOptions(Options{TombstoneTTL: time.Millisecond})
+
+	for _, id := range []string{"billy", "sally"} {
+		if err := index.Index(id, TestRecord{Name: id}); err != nil {
+			t.Fatal(err)
+		}
+		if err := index.DeleteOptions(id, WriteOptions{Version: 7}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	upsert := func() error {
+		res, err := index.Bulk([]BulkOperation{
+			{Type: "upsert", ID: "billy", Document: TestRecord{Name: "billy"}, WriteOptions: WriteOptions{Version: 6}},
+		})
+		if err != nil {
+			return err
+		}
+		return res.Items[0].Error
+	}
+	if err := upsert(); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("expected older upsert before the tombstone expired to conflict, got %v", err)
+	}
+
+	// Once expired an older write is taken as a new document
+	time.Sleep(5 * time.Millisecond)
+	if err := upsert(); err != nil {
+		t.Errorf("expected older upsert after the tombstone expired to succeed, got %v", err)
+	}
+	if len(index.tombstones) != 0 || len(index.tombstoneOrder) != 0 {
+		t.Errorf("expected expired tombstones to be removed, got %d and %d", len(index.tombstones), len(index.tombstoneOrder))
+	}
+}
+
+func TestIndex_Bulk_failedWriteSeqNo(t *testing.T) {
+	index := New()
+
+	// The second vector has the wrong dims, which is only
+	// found once the document is being added
+	res, err := index.Bulk([]BulkOperation{
+		{Type: "index", ID: "1", Document: TestEmbedding{Name: "one", Embedding: []float32{1, 2, 3}}},
+		{Type: "index", ID: "2", Document: TestEmbedding{Name: "two", Embedding: []float32{1, 2}}},
+		{Type: "index", ID: "3", Document: TestEmbedding{Name: "three", Embedding: []float32{1, 2, 3}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Items[1].Error == nil {
+		t.Fatal("expected the wrong dims to fail")
+	}
+	if res.Items[2].SeqNo != 2 || index.seqNo != 2 {
+		t.Errorf("expected the failed write to not use a seq no, got %d and %d", res.Items[2].SeqNo, index.seqNo)
+	}
+}